@@ -0,0 +1,160 @@
+package chio
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// BatchWriter is an io.Writer that accumulates packets written through it,
+// and writes them to the underlying stream in a single call. Since every
+// WritePacket call results in exactly one Write, packets are never split.
+//
+// Buffered data is written on an explicit Flush, as soon as the buffer
+// grows past the size threshold, or once the flush delay has passed since
+// the first buffered packet, whichever comes first.
+//
+// Data that could not be written is kept in the buffer, and written again on
+// the next flush, or once the flush delay has passed again. Write never fails,
+// so errors of flushes that happen in the background are returned by Err,
+// and by the next call to Flush.
+type BatchWriter struct {
+	stream     io.Writer
+	buffer     bytes.Buffer
+	threshold  int
+	delay      time.Duration
+	timer      *time.Timer
+	generation uint64 // Incremented whenever the timer is started or stopped
+	armed      bool
+	err        error // Error of the last flush that happened in the background
+	mu         sync.Mutex
+}
+
+// NewBatchWriter creates a BatchWriter around the provided stream.
+// A threshold of 0 disables size-based flushing, and a delay of 0 disables
+// deadline-based flushing, leaving it to explicit Flush calls.
+func NewBatchWriter(stream io.Writer, threshold int, delay time.Duration) *BatchWriter {
+	return &BatchWriter{
+		stream:    stream,
+		threshold: threshold,
+		delay:     delay,
+	}
+}
+
+// Write appends p to the batch. The data is always accepted, even if the
+// flush that it triggers fails, in which case the error is kept for Err & Flush.
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.armed && w.delay > 0 {
+		w.startTimer()
+	}
+
+	n, _ := w.buffer.Write(p)
+
+	if w.threshold > 0 && w.buffer.Len() >= w.threshold {
+		w.err = w.flush()
+	}
+
+	return n, nil
+}
+
+// Err returns the error of the last flush that happened in the background,
+// i.e. because of the size threshold or flush delay, if no flush succeeded since
+func (w *BatchWriter) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Flush writes all buffered packets to the underlying stream
+func (w *BatchWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.takeError(); err != nil && w.buffer.Len() == 0 {
+		return err
+	}
+	return w.flush()
+}
+
+// Buffered returns the number of bytes waiting to be flushed
+func (w *BatchWriter) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buffer.Len()
+}
+
+// Bytes returns the buffered data and resets the batch, without writing
+// to the underlying stream. This is useful for transports that build
+// a response body, like the HTTP based bancho protocol.
+func (w *BatchWriter) Bytes() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopTimer()
+
+	data := bytes.Clone(w.buffer.Bytes())
+	w.buffer.Reset()
+	return data
+}
+
+// Close flushes the remaining packets and stops the flush timer.
+// It does not close the underlying stream.
+func (w *BatchWriter) Close() error {
+	return w.Flush()
+}
+
+func (w *BatchWriter) flush() error {
+	w.stopTimer()
+	w.err = nil
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	// Only the data that was written is discarded, the rest will be retried
+	n, err := w.stream.Write(w.buffer.Bytes())
+	w.buffer.Next(n)
+
+	if w.buffer.Len() > 0 && w.delay > 0 {
+		w.startTimer()
+	}
+	return err
+}
+
+// takeError returns the error of the last background flush once
+func (w *BatchWriter) takeError() error {
+	err := w.err
+	w.err = nil
+	return err
+}
+
+func (w *BatchWriter) startTimer() {
+	w.generation++
+	w.armed = true
+	generation := w.generation
+
+	w.timer = time.AfterFunc(w.delay, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		// The timer may have fired while it was being stopped or restarted,
+		// in which case the flush belongs to an earlier batch
+		if generation != w.generation {
+			return
+		}
+		w.err = w.flush()
+	})
+}
+
+func (w *BatchWriter) stopTimer() {
+	w.generation++
+	w.armed = false
+
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
@@ -0,0 +1,224 @@
+package chio
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingStream records every call to Write, and can fail a number of them
+type recordingStream struct {
+	writes   [][]byte
+	failures int
+	written  chan struct{}
+	mu       sync.Mutex
+}
+
+var errTransient = errors.New("transient error")
+
+func newRecordingStream() *recordingStream {
+	return &recordingStream{written: make(chan struct{}, 16)}
+}
+
+func (stream *recordingStream) Write(p []byte) (int, error) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if stream.failures > 0 {
+		stream.failures--
+		return 0, errTransient
+	}
+
+	stream.writes = append(stream.writes, bytes.Clone(p))
+	stream.written <- struct{}{}
+	return len(p), nil
+}
+
+func (stream *recordingStream) Writes() [][]byte {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	return append([][]byte(nil), stream.writes...)
+}
+
+func expectWrites(t *testing.T, stream *recordingStream, expected ...string) {
+	t.Helper()
+
+	writes := stream.Writes()
+	if len(writes) != len(expected) {
+		t.Fatalf("expected %d writes, got %q", len(expected), writes)
+	}
+	for i, write := range writes {
+		if string(write) != expected[i] {
+			t.Fatalf("expected write %d to be %q, got %q", i, expected[i], write)
+		}
+	}
+}
+
+func TestBatchThreshold(t *testing.T) {
+	stream := newRecordingStream()
+	writer := NewBatchWriter(stream, 8, 0)
+
+	writer.Write([]byte("ping"))
+	expectWrites(t, stream)
+
+	writer.Write([]byte("pong"))
+	expectWrites(t, stream, "pingpong")
+
+	if writer.Buffered() != 0 {
+		t.Fatalf("expected an empty buffer, got %d bytes", writer.Buffered())
+	}
+}
+
+func TestBatchDeadline(t *testing.T) {
+	stream := newRecordingStream()
+	writer := NewBatchWriter(stream, 0, 5*time.Millisecond)
+
+	writer.Write([]byte("ping"))
+	writer.Write([]byte("pong"))
+
+	select {
+	case <-stream.written:
+	case <-time.After(time.Second):
+		t.Fatal("batch was not flushed after the delay")
+	}
+	expectWrites(t, stream, "pingpong")
+}
+
+func TestBatchFlush(t *testing.T) {
+	stream := newRecordingStream()
+	writer := NewBatchWriter(stream, 0, 0)
+
+	writer.Write([]byte("ping"))
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expectWrites(t, stream, "ping")
+
+	writer.Write([]byte("pong"))
+	if data := writer.Bytes(); string(data) != "pong" {
+		t.Fatalf("expected the buffered data, got %q", data)
+	}
+	expectWrites(t, stream, "ping")
+}
+
+func TestBatchErrorKeepsData(t *testing.T) {
+	stream := newRecordingStream()
+	stream.failures = 1
+	writer := NewBatchWriter(stream, 0, 0)
+
+	writer.Write([]byte("ping"))
+	if err := writer.Flush(); !errors.Is(err, errTransient) {
+		t.Fatalf("expected the error of the stream, got %v", err)
+	}
+	if _, err := writer.Write([]byte("pong")); err != nil {
+		t.Fatalf("expected writes to be accepted after a failed flush, got %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expectWrites(t, stream, "pingpong")
+}
+
+func TestBatchThresholdError(t *testing.T) {
+	stream := newRecordingStream()
+	stream.failures = 1
+	writer := NewBatchWriter(stream, 4, 0)
+
+	// The packet is queued, so its write must not fail
+	if _, err := writer.Write([]byte("ping")); err != nil {
+		t.Fatalf("expected the write to be accepted, got %v", err)
+	}
+	if err := writer.Err(); !errors.Is(err, errTransient) {
+		t.Fatalf("expected the error of the threshold flush, got %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Err(); err != nil {
+		t.Fatalf("expected the error to be cleared, got %v", err)
+	}
+	expectWrites(t, stream, "ping")
+}
+
+func TestBatchDeadlineRetry(t *testing.T) {
+	stream := newRecordingStream()
+	stream.failures = 1
+	writer := NewBatchWriter(stream, 0, time.Millisecond)
+
+	if _, err := writer.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first deadline flush fails, which has to arm the timer again
+	select {
+	case <-stream.written:
+	case <-time.After(time.Second):
+		t.Fatal("batch was not flushed again after a failed flush")
+	}
+	expectWrites(t, stream, "ping")
+
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBatchStaleTimer(t *testing.T) {
+	stream := newRecordingStream()
+	writer := NewBatchWriter(stream, 0, time.Millisecond)
+
+	// Let the timer fire while the batch is flushed & restarted,
+	// so that its callback has to wait for the lock
+	writer.mu.Lock()
+	writer.startTimer()
+	writer.buffer.WriteString("ping")
+	time.Sleep(20 * time.Millisecond)
+	writer.flush()
+
+	writer.delay = time.Hour
+	writer.startTimer()
+	writer.buffer.WriteString("pong")
+	writer.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+	expectWrites(t, stream, "ping")
+
+	if writer.Buffered() != 4 {
+		t.Fatalf("expected the next batch to stay buffered, got %d bytes", writer.Buffered())
+	}
+	writer.Close()
+}
+
+func TestBatchConcurrentWrites(t *testing.T) {
+	stream := newRecordingStream()
+	stream.written = make(chan struct{}, 1024)
+	writer := NewBatchWriter(stream, 64, time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				writer.Write([]byte("packet"))
+			}
+		}()
+	}
+	wg.Wait()
+	writer.Close()
+
+	total := 0
+	for _, write := range stream.Writes() {
+		if len(write)%len("packet") != 0 {
+			t.Fatalf("packet was split across writes: %q", write)
+		}
+		total += len(write)
+	}
+	if total != 8*50*len("packet") {
+		t.Fatalf("expected %d bytes, got %d", 8*50*len("packet"), total)
+	}
+}
@@ -0,0 +1,68 @@
+package chio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PacketWriterFunc writes one logical packet through the writers of a client,
+// e.g. func(c BanchoIO, w io.Writer) error { return c.WriteMessage(w, msg) }
+type PacketWriterFunc func(client BanchoIO, stream io.Writer) error
+
+// Recipient is a single destination of a broadcast
+type Recipient struct {
+	IO     BanchoIO
+	Stream io.Writer
+}
+
+// Broadcast sends a packet to every recipient, while only encoding it once
+// per client implementation. The cached bytes are then written to each stream.
+// Errors of individual recipients do not stop the broadcast, and are returned
+// together once every stream was written to.
+func Broadcast(recipients []Recipient, write PacketWriterFunc) error {
+	groups := make(map[BanchoIO][]io.Writer)
+	order := make([]BanchoIO, 0, 1)
+
+	for _, recipient := range recipients {
+		if _, ok := groups[recipient.IO]; !ok {
+			order = append(order, recipient.IO)
+		}
+		groups[recipient.IO] = append(groups[recipient.IO], recipient.Stream)
+	}
+
+	var errs []error
+	encoded := new(bytes.Buffer)
+
+	for _, client := range order {
+		encoded.Reset()
+
+		if err := write(client, encoded); err != nil {
+			errs = append(errs, fmt.Errorf("failed to encode packet for protocol %d: %w", client.ProtocolVersion(), err))
+			continue
+		}
+
+		if encoded.Len() == 0 {
+			// Packet is not supported by this client
+			continue
+		}
+
+		for _, stream := range groups[client] {
+			if _, err := stream.Write(encoded.Bytes()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// BroadcastTo is a shorthand for Broadcast, for when all streams use the same client
+func BroadcastTo(client BanchoIO, streams []io.Writer, write PacketWriterFunc) error {
+	recipients := make([]Recipient, len(streams))
+	for i, stream := range streams {
+		recipients[i] = Recipient{IO: client, Stream: stream}
+	}
+	return Broadcast(recipients, write)
+}
@@ -0,0 +1,94 @@
+package chio_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/chiotest"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+var errStream = errors.New("stream closed")
+
+type failingStream struct{}
+
+func (failingStream) Write(p []byte) (int, error) {
+	return 0, errStream
+}
+
+func TestBroadcastGroupsVersions(t *testing.T) {
+	modern, old := clients.NewB323(), clients.NewB294()
+	streams := []*chiotest.Stream{chiotest.NewStream(modern), chiotest.NewStream(old), chiotest.NewStream(modern)}
+
+	recipients := make([]chio.Recipient, len(streams))
+	for i, stream := range streams {
+		recipients[i] = chio.Recipient{IO: stream.IO, Stream: stream}
+	}
+
+	encodes := make(map[chio.BanchoIO]int)
+	message := chio.Message{Sender: "peppy", Content: "hello", Target: "#osu"}
+
+	err := chio.Broadcast(recipients, func(client chio.BanchoIO, stream io.Writer) error {
+		encodes[client]++
+		return client.WriteMessage(stream, message)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(encodes) != 2 || encodes[modern] != 1 || encodes[old] != 1 {
+		t.Fatalf("expected one encode per client, got %v", encodes)
+	}
+	for _, stream := range streams {
+		chiotest.ExpectWhere(t, stream, chio.BanchoSendMessage, func(received chio.Message) bool {
+			return received.Content == message.Content
+		})
+		chiotest.ExpectNone(t, stream)
+		chiotest.ExpectNoError(t, stream)
+	}
+}
+
+func TestBroadcastSkipsUnsupported(t *testing.T) {
+	old := clients.NewB282()
+	old.OverrideFallback(chio.NewFallbackPolicy(chio.FallbackDrop))
+	stream := new(bytes.Buffer)
+
+	err := chio.BroadcastTo(old, []io.Writer{stream}, func(client chio.BanchoIO, stream io.Writer) error {
+		return client.WriteAnnouncement(stream, "maintenance")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stream.Len() != 0 {
+		t.Fatalf("expected no data for an unsupported packet, got %d bytes", stream.Len())
+	}
+}
+
+func TestBroadcastErrors(t *testing.T) {
+	modern, old := clients.NewB323(), clients.NewB294()
+	received := new(bytes.Buffer)
+	errEncode := errors.New("encode failed")
+
+	recipients := []chio.Recipient{
+		{IO: modern, Stream: failingStream{}},
+		{IO: modern, Stream: received},
+		{IO: old, Stream: new(bytes.Buffer)},
+	}
+
+	err := chio.Broadcast(recipients, func(client chio.BanchoIO, stream io.Writer) error {
+		if client == old {
+			return errEncode
+		}
+		return client.WritePing(stream)
+	})
+
+	if !errors.Is(err, errStream) || !errors.Is(err, errEncode) {
+		t.Fatalf("expected both the stream and the encode error, got %v", err)
+	}
+	if received.Len() == 0 {
+		t.Fatal("a failing stream stopped the broadcast")
+	}
+}
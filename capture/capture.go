@@ -0,0 +1,145 @@
+// Package capture implements a simple file format for recording bancho
+// sessions, as well as a recorder and replayer built on top of it.
+//
+// A capture starts with an 8 byte magic, followed by any amount of records:
+//
+//	uint32  length of the record, excluding this field
+//	int64   timestamp in unix nanoseconds
+//	uint8   direction of the frame
+//	int32   client version
+//	[]byte  raw packet frame, as it was sent over the wire
+//
+// All integers are little endian.
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/internal"
+)
+
+// Magic is written at the start of every capture
+var Magic = []byte("chiocap1")
+
+// recordHeaderSize is the size of a record without its frame
+const recordHeaderSize = 8 + 1 + 4
+
+// maxRecordSize protects readers from allocating huge buffers for corrupted captures
+const maxRecordSize = 64 * 1024 * 1024
+
+type Direction = chio.Direction
+
+const (
+	ClientToServer = chio.DirectionClientToServer
+	ServerToClient = chio.DirectionServerToClient
+)
+
+// Record is a single captured packet frame
+type Record struct {
+	Time      time.Time
+	Direction Direction
+	Version   int
+	Frame     []byte
+}
+
+// Writer writes records to a capture
+type Writer struct {
+	w           io.Writer
+	wroteHeader bool
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) WriteRecord(record Record) error {
+	if !w.wroteHeader {
+		if _, err := w.w.Write(Magic); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+
+	buffer := internal.GetBuffer()
+	defer internal.PutBuffer(buffer)
+
+	internal.WriteUint32(buffer, uint32(recordHeaderSize+len(record.Frame)))
+	internal.WriteInt64(buffer, record.Time.UnixNano())
+	internal.WriteUint8(buffer, uint8(record.Direction))
+	internal.WriteInt32(buffer, int32(record.Version))
+	buffer.Write(record.Frame)
+
+	_, err := w.w.Write(buffer.Bytes())
+	return err
+}
+
+// Reader reads records from a capture
+type Reader struct {
+	r          *bufio.Reader
+	readHeader bool
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// ReadRecord returns the next record, or io.EOF at the end of the capture
+func (r *Reader) ReadRecord() (*Record, error) {
+	if !r.readHeader {
+		magic := make([]byte, len(Magic))
+		if _, err := io.ReadFull(r.r, magic); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(magic, Magic) {
+			return nil, errors.New("not a chio capture")
+		}
+		r.readHeader = true
+	}
+
+	length, err := internal.ReadUint32(r.r)
+	if err != nil {
+		return nil, err
+	}
+	if length < recordHeaderSize || length > maxRecordSize {
+		return nil, fmt.Errorf("invalid record length %d", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return nil, err
+	}
+
+	reader := bytes.NewReader(data)
+	timestamp, _ := internal.ReadInt64(reader)
+	direction, _ := internal.ReadUint8(reader)
+	version, _ := internal.ReadInt32(reader)
+
+	return &Record{
+		Time:      time.Unix(0, timestamp),
+		Direction: Direction(direction),
+		Version:   int(version),
+		Frame:     data[recordHeaderSize:],
+	}, nil
+}
+
+// ReadAll reads all remaining records of the capture
+func (r *Reader) ReadAll() ([]*Record, error) {
+	records := make([]*Record, 0)
+
+	for {
+		record, err := r.ReadRecord()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
@@ -0,0 +1,155 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/chiotest"
+	"github.com/Lekuruu/chio-go/clients"
+	"github.com/Lekuruu/chio-go/internal"
+)
+
+// recordSession records a short session on the server side, and returns the capture
+func recordSession(t *testing.T) []byte {
+	t.Helper()
+
+	server := clients.NewB323()
+	conn := chiotest.NewConn(server)
+	conn.Script(
+		func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteSendIrcMessage(stream, chio.Message{Content: "hello", Target: "#osu"})
+		},
+		func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteStartSpectating(stream, 2)
+		},
+	)
+
+	capture := new(bytes.Buffer)
+	recorder := NewRecorder(conn, NewWriter(capture), 323, ClientToServer)
+
+	for conn.Len() > 0 {
+		if _, err := server.ReadPacket(recorder); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := server.WriteMessage(recorder, chio.Message{Sender: "peppy", Content: "hi", Target: "#osu"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.WriteSpectatorJoined(recorder, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := recorder.Err(); err != nil {
+		t.Fatal(err)
+	}
+	chiotest.ExpectNext(t, conn.Stream, chio.BanchoSendMessage)
+	chiotest.ExpectNext(t, conn.Stream, chio.BanchoSpectatorJoined)
+	return capture.Bytes()
+}
+
+func TestRecordReplay(t *testing.T) {
+	records, err := NewReader(bytes.NewReader(recordSession(t))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected 4 records, got %d", len(records))
+	}
+
+	directions := []Direction{ClientToServer, ClientToServer, ServerToClient, ServerToClient}
+	for i, record := range records {
+		if record.Direction != directions[i] || record.Version != 323 {
+			t.Fatalf("record %d has direction %d and version %d", i, record.Direction, record.Version)
+		}
+	}
+
+	results := Replay(records, nil)
+	for i, result := range results {
+		if result.Err != nil {
+			t.Fatalf("record %d: %v", i, result.Err)
+		}
+	}
+
+	expected := new(bytes.Buffer)
+	if err := SaveResults(expected, results); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadResults(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mismatches := Compare(loaded, Replay(records, nil)); len(mismatches) != 0 {
+		t.Fatalf("expected no mismatches, got %v", mismatches)
+	}
+
+	// b282 can't decode the packets of b323 the same way
+	if mismatches := Compare(loaded, Replay(records, clients.NewB282())); len(mismatches) == 0 {
+		t.Fatal("expected mismatches when replaying with another version")
+	}
+}
+
+func TestRecordLogin(t *testing.T) {
+	// Small buffers split the login lines and frames across reads
+	for _, size := range []int{16, 4096} {
+		server := clients.NewB323()
+		conn := chiotest.NewConn(server)
+		io.WriteString(conn.Client, "peppy\r\n5f4dcc3b5aa765d61d8327deb882cf99\r\nb323|1|0|abc:def:|0\r\n")
+		conn.Script(
+			func(osu chio.OsuIO, stream io.Writer) error {
+				return osu.WriteSendIrcMessage(stream, chio.Message{Content: "hello", Target: "#osu"})
+			},
+			func(osu chio.OsuIO, stream io.Writer) error {
+				return osu.WriteStartSpectating(stream, 2)
+			},
+		)
+
+		capture := new(bytes.Buffer)
+		recorder := NewRecorder(conn, NewWriter(capture), 323, ClientToServer).WithLogin()
+		reader := bufio.NewReaderSize(recorder, size)
+
+		for i := 0; i < LoginLines; i++ {
+			if _, err := reader.ReadString('\n'); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := server.ReadPacket(reader); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := recorder.Err(); err != nil {
+			t.Fatalf("buffer of %d bytes: %v", size, err)
+		}
+		records, err := NewReader(capture).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("buffer of %d bytes: expected 2 records, got %d", size, len(records))
+		}
+		for i, result := range Replay(records, nil) {
+			if result.Err != nil {
+				t.Fatalf("record %d: %v", i, result.Err)
+			}
+		}
+	}
+}
+
+func TestRecorderFrameTooLarge(t *testing.T) {
+	frame := []byte{1, 0, 0xff, 0xff, 0xff, 0xff}
+	conn := chiotest.NewConn(clients.NewB323())
+	conn.Client.Write(frame)
+
+	recorder := NewRecorder(conn, NewWriter(new(bytes.Buffer)), 323, ClientToServer)
+	io.ReadAll(recorder)
+
+	if err := recorder.Err(); !errors.Is(err, internal.ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Lekuruu/chio-go/internal"
+)
+
+// LoginLines is the amount of plaintext lines that a client sends before its first
+// packet, i.e. the username, password hash and client info
+const LoginLines = 3
+
+// Recorder wraps the stream of a session and records every packet frame that
+// passes through it. It can be passed to ReadPacket and WritePacket directly.
+// Reads & writes are reassembled into whole frames, so it doesn't matter how
+// the data is split up between calls.
+//
+// Recording has to start after the login of the client, unless WithLogin is
+// used, since the login is not made up of frames.
+type Recorder struct {
+	Stream  io.ReadWriter
+	Capture *Writer
+	Version int
+
+	// Direction of the data that is read from the stream,
+	// e.g. ClientToServer when recording on the server side
+	Inbound Direction
+
+	inbound  internal.FrameBuffer
+	outbound internal.FrameBuffer
+	login    int // Login lines that were not sent by the client yet
+	err      error
+	mu       sync.Mutex
+}
+
+func NewRecorder(stream io.ReadWriter, capture *Writer, version int, inbound Direction) *Recorder {
+	return &Recorder{
+		Stream:  stream,
+		Capture: capture,
+		Version: version,
+		Inbound: inbound,
+	}
+}
+
+// WithLogin makes the recorder skip the plaintext login of the client, for recordings
+// that start with the connection. The login is not recorded, since it contains the password.
+func (r *Recorder) WithLogin() *Recorder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.login = LoginLines
+	return r
+}
+
+func (r *Recorder) Read(p []byte) (int, error) {
+	n, err := r.Stream.Read(p)
+	if n > 0 {
+		r.record(&r.inbound, p[:n], r.Inbound)
+	}
+	return n, err
+}
+
+func (r *Recorder) Write(p []byte) (int, error) {
+	n, err := r.Stream.Write(p)
+	if n > 0 {
+		r.record(&r.outbound, p[:n], r.outboundDirection())
+	}
+	return n, err
+}
+
+// Err returns the first error that occurred while writing to the capture,
+// or while reassembling the frames of the stream
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) outboundDirection() Direction {
+	if r.Inbound == ClientToServer {
+		return ServerToClient
+	}
+	return ClientToServer
+}
+
+// record appends data to the pending buffer, and writes every complete frame to the capture
+func (r *Recorder) record(pending *internal.FrameBuffer, data []byte, direction Direction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if direction == ClientToServer {
+		data = r.skipLogin(data)
+	}
+	pending.Write(data)
+
+	for {
+		frame, err := pending.Next()
+		if err != nil {
+			r.fail(err)
+			return
+		}
+		if frame == nil {
+			return
+		}
+
+		record := Record{
+			Time:      time.Now(),
+			Direction: direction,
+			Version:   r.Version,
+			Frame:     frame,
+		}
+		r.fail(r.Capture.WriteRecord(record))
+	}
+}
+
+// skipLogin returns the part of data that follows the remaining login lines
+func (r *Recorder) skipLogin(data []byte) []byte {
+	for r.login > 0 {
+		index := bytes.IndexByte(data, '\n')
+		if index < 0 {
+			return nil
+		}
+		data = data[index+1:]
+		r.login--
+	}
+	return data
+}
+
+// fail remembers the first error of the recorder
+func (r *Recorder) fail(err error) {
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+}
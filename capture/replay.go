@@ -0,0 +1,144 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+// Result is the outcome of decoding a single record
+type Result struct {
+	Record *Record
+	Packet *chio.BanchoPacket
+	Err    error
+
+	// description of a result that was loaded with LoadResults
+	description string
+}
+
+// Mismatch describes a record that was decoded differently between two replays
+type Mismatch struct {
+	Index    int
+	Expected string
+	Actual   string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("record %d: expected %s, got %s", m.Index, m.Expected, m.Actual)
+}
+
+// Replay feeds every record back through ReadPacket of the provided client.
+// Frames sent by the client are decoded on the server side, and frames sent
+// by the server on the client side. If client is nil, every record is decoded
+// with the client version it was recorded with.
+func Replay(records []*Record, client chio.BanchoIO) []Result {
+	results := make([]Result, len(records))
+	osuClients := make(map[chio.BanchoIO]*clients.OsuClient)
+
+	for i, record := range records {
+		io := client
+		if io == nil {
+			io = chio.GetClientInterface(record.Version)
+		}
+
+		result := Result{Record: record}
+		if io == nil {
+			result.Err = fmt.Errorf("no client for version %d", record.Version)
+			results[i] = result
+			continue
+		}
+
+		stream := bytes.NewReader(record.Frame)
+
+		if record.Direction == ClientToServer {
+			result.Packet, result.Err = io.ReadPacket(stream)
+		} else {
+			if _, ok := osuClients[io]; !ok {
+				osuClients[io] = clients.NewOsuClient(io)
+			}
+			result.Packet, result.Err = osuClients[io].ReadPacket(stream)
+		}
+
+		if result.Err == nil && stream.Len() > 0 {
+			result.Err = fmt.Errorf("%d trailing bytes after packet '%d'", stream.Len(), result.Packet.Id)
+		}
+
+		results[i] = result
+	}
+
+	return results
+}
+
+// Compare returns every result of actual, that was not decoded the same way as in expected.
+// Decoded packets are compared by their JSON representation, so that expected results can
+// also be loaded from a file that was written with SaveResults.
+func Compare(expected []Result, actual []Result) []Mismatch {
+	mismatches := make([]Mismatch, 0)
+
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		var expectedResult, actualResult string
+
+		if i < len(expected) {
+			expectedResult = describe(expected[i])
+		}
+		if i < len(actual) {
+			actualResult = describe(actual[i])
+		}
+
+		if expectedResult != actualResult {
+			mismatches = append(mismatches, Mismatch{Index: i, Expected: expectedResult, Actual: actualResult})
+		}
+	}
+
+	return mismatches
+}
+
+// SaveResults writes the representation of every result, which is used by Compare.
+// Together with LoadResults, this allows keeping the results of a capture around
+// as the expected output of a regression test.
+func SaveResults(w io.Writer, results []Result) error {
+	descriptions := make([]string, len(results))
+	for i, result := range results {
+		descriptions[i] = describe(result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(descriptions)
+}
+
+// LoadResults reads results that were written with SaveResults. The loaded results
+// only contain their representation, and can only be used with Compare.
+func LoadResults(r io.Reader) ([]Result, error) {
+	var descriptions []string
+	if err := json.NewDecoder(r).Decode(&descriptions); err != nil {
+		return nil, fmt.Errorf("failed to load results: %w", err)
+	}
+
+	results := make([]Result, len(descriptions))
+	for i, description := range descriptions {
+		results[i] = Result{description: description}
+	}
+	return results, nil
+}
+
+// describe returns a stable representation of a result, used for comparisons
+func describe(result Result) string {
+	if result.description != "" {
+		return result.description
+	}
+	if result.Err != nil {
+		return "error: " + result.Err.Error()
+	}
+
+	data, err := json.Marshal(result.Packet)
+	if err != nil {
+		return fmt.Sprintf("%+v", result.Packet)
+	}
+
+	return string(data)
+}
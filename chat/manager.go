@@ -0,0 +1,443 @@
+// Package chat keeps track of channels & their members, and delivers
+// messages to every recipient in the way that its client version expects.
+//
+// A server adds every connected player to a Manager, and passes the chat
+// packets it reads from them to Handle:
+//
+//	manager.Add(&chio.Session{Id: user.Id, Name: user.Name, IO: io, Stream: conn})
+//	defer manager.Remove(user.Id)
+//
+//	for {
+//		packet, err := io.ReadPacket(conn)
+//		...
+//		manager.Handle(user.Id, packet)
+//	}
+//
+// Versions differ in how they can display messages:
+//
+//   - b282 only knows #osu, and can't receive private messages
+//   - b294 marks messages as either public or private, and only knows #osu
+//   - b320 sends the target of every message
+//
+// Recipients that can't display the target of a message receive it in #osu,
+// prefixed with the name of the channel or recipient, e.g. "(#lobby) hello".
+// This happens regardless of the fallback strategy of the client.
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+var (
+	ErrUnknownPlayer  = errors.New("unknown player")
+	ErrUnknownChannel = errors.New("unknown channel")
+	ErrChannelExists  = errors.New("channel already exists")
+	ErrNotMember      = errors.New("player is not a member of the channel")
+)
+
+// DefaultChannel is the channel that every version supports
+const DefaultChannel = "#osu"
+
+// channel is a chat channel, and the ids of its members
+type channel struct {
+	chio.Channel
+	autojoin bool
+	members  map[int32]bool
+}
+
+// Manager tracks channels & their members
+type Manager struct {
+	players  map[int32]*chio.Session
+	names    map[string]int32
+	channels map[string]*channel
+	mu       sync.Mutex
+}
+
+// NewManager creates a manager with DefaultChannel, which every player joins
+func NewManager() *Manager {
+	manager := &Manager{
+		players:  make(map[int32]*chio.Session),
+		names:    make(map[string]int32),
+		channels: make(map[string]*channel),
+	}
+	manager.Create(chio.Channel{Name: DefaultChannel, Topic: "General discussion."}, true)
+	return manager
+}
+
+// Add registers a player, sends it the available channels
+// and lets it join every channel that is joined automatically.
+// A previous player with the same id is replaced, and leaves its channels.
+func (manager *Manager) Add(player *chio.Session) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		left := make([]*channel, 0)
+		if previous, ok := manager.players[player.Id]; ok {
+			delete(manager.names, previous.Name)
+
+			// The new connection has to join its channels again
+			for _, channel := range manager.sortedChannels() {
+				if channel.members[player.Id] {
+					delete(channel.members, player.Id)
+					if !channel.autojoin {
+						left = append(left, channel)
+					}
+				}
+			}
+		}
+		manager.players[player.Id] = player
+		manager.names[player.Name] = player.Id
+
+		errs := make([]error, 0)
+		for _, channel := range manager.sortedChannels() {
+			if channel.autojoin {
+				errs = append(errs, player.IO.WriteChannelAvailableAutojoin(outbox.Stream(player), channel.info()))
+			} else {
+				errs = append(errs, player.IO.WriteChannelAvailable(outbox.Stream(player), channel.info()))
+			}
+		}
+		errs = append(errs, player.IO.WriteChannelInfoComplete(outbox.Stream(player)))
+
+		for _, channel := range manager.sortedChannels() {
+			if channel.autojoin {
+				errs = append(errs, manager.join(outbox, player, channel))
+			}
+		}
+		for _, channel := range left {
+			errs = append(errs, manager.broadcastInfo(outbox, channel))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Remove handles the disconnect of a player, which leaves every channel
+func (manager *Manager) Remove(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		player, ok := manager.players[id]
+		if !ok {
+			return nil
+		}
+		delete(manager.players, id)
+		delete(manager.names, player.Name)
+
+		errs := make([]error, 0)
+		for _, channel := range manager.sortedChannels() {
+			if channel.members[id] {
+				delete(channel.members, id)
+				errs = append(errs, manager.broadcastInfo(outbox, channel))
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Handle applies a chat packet that was read from a player. Other packets are ignored.
+// None of the supported versions send OsuChannelJoin or OsuChannelLeave yet, they
+// are applied for clients that register readers for them.
+func (manager *Manager) Handle(id int32, packet *chio.BanchoPacket) error {
+	switch packet.Id {
+	case chio.OsuSendIrcMessage, chio.OsuSendIrcMessagePrivate:
+		message, err := chio.Decode[chio.Message](packet)
+		if err != nil {
+			return err
+		}
+		return manager.Send(id, message)
+	case chio.OsuChannelJoin:
+		join, err := chio.Decode[chio.ChannelJoin](packet)
+		if err != nil {
+			return err
+		}
+		return manager.Join(id, join.Name)
+	case chio.OsuChannelLeave:
+		leave, err := chio.Decode[chio.ChannelLeave](packet)
+		if err != nil {
+			return err
+		}
+		return manager.Leave(id, leave.Name)
+	}
+	return nil
+}
+
+// Create opens a channel, and announces it to every player
+func (manager *Manager) Create(info chio.Channel, autojoin bool) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		if _, ok := manager.channels[info.Name]; ok {
+			return fmt.Errorf("channel %s: %w", info.Name, ErrChannelExists)
+		}
+		channel := &channel{Channel: info, autojoin: autojoin, members: make(map[int32]bool)}
+		manager.channels[info.Name] = channel
+
+		errs := make([]error, 0)
+		for _, player := range manager.sortedPlayers() {
+			if autojoin {
+				errs = append(errs, player.IO.WriteChannelAvailableAutojoin(outbox.Stream(player), channel.info()))
+				errs = append(errs, manager.join(outbox, player, channel))
+			} else {
+				errs = append(errs, player.IO.WriteChannelAvailable(outbox.Stream(player), channel.info()))
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Close removes a channel, which is revoked from its members
+func (manager *Manager) Close(name string) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		channel, ok := manager.channels[name]
+		if !ok {
+			return fmt.Errorf("channel %s: %w", name, ErrUnknownChannel)
+		}
+		delete(manager.channels, name)
+
+		errs := make([]error, 0)
+		for _, member := range manager.members(channel) {
+			errs = append(errs, member.IO.WriteChannelRevoked(outbox.Stream(member), name))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// SetTopic changes the topic of a channel, which is announced to every player
+func (manager *Manager) SetTopic(name string, topic string) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		channel, ok := manager.channels[name]
+		if !ok {
+			return fmt.Errorf("channel %s: %w", name, ErrUnknownChannel)
+		}
+		channel.Topic = topic
+		return manager.broadcastInfo(outbox, channel)
+	})
+}
+
+// Join adds a player to a channel
+func (manager *Manager) Join(id int32, name string) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		player, ok := manager.players[id]
+		if !ok {
+			return fmt.Errorf("player %d: %w", id, ErrUnknownPlayer)
+		}
+		channel, ok := manager.channels[name]
+		if !ok {
+			return errors.Join(
+				fmt.Errorf("channel %s: %w", name, ErrUnknownChannel),
+				player.IO.WriteChannelRevoked(outbox.Stream(player), name),
+			)
+		}
+		return manager.join(outbox, player, channel)
+	})
+}
+
+func (manager *Manager) join(outbox *chio.Outbox, player *chio.Session, channel *channel) error {
+	if channel.members[player.Id] {
+		return nil
+	}
+	channel.members[player.Id] = true
+
+	errs := []error{player.IO.WriteChannelJoinSuccess(outbox.Stream(player), channel.Name)}
+	errs = append(errs, manager.broadcastInfo(outbox, channel))
+	return errors.Join(errs...)
+}
+
+// Leave removes a player from a channel
+func (manager *Manager) Leave(id int32, name string) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		channel, ok := manager.channels[name]
+		if !ok {
+			return fmt.Errorf("channel %s: %w", name, ErrUnknownChannel)
+		}
+		if !channel.members[id] {
+			return nil
+		}
+		delete(channel.members, id)
+		return manager.broadcastInfo(outbox, channel)
+	})
+}
+
+// Revoke removes a player from a channel, and lets its client know about it
+func (manager *Manager) Revoke(id int32, name string) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		channel, ok := manager.channels[name]
+		if !ok {
+			return fmt.Errorf("channel %s: %w", name, ErrUnknownChannel)
+		}
+		player, ok := manager.players[id]
+		if !ok || !channel.members[id] {
+			return ErrNotMember
+		}
+		delete(channel.members, id)
+
+		errs := []error{player.IO.WriteChannelRevoked(outbox.Stream(player), name)}
+		errs = append(errs, manager.broadcastInfo(outbox, channel))
+		return errors.Join(errs...)
+	})
+}
+
+// Send delivers a message of a player to the members of a channel, or
+// to another player, if the target is not a channel. The sender is taken
+// from the player, and does not receive its own message.
+func (manager *Manager) Send(id int32, message chio.Message) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		sender, ok := manager.players[id]
+		if !ok {
+			return fmt.Errorf("player %d: %w", id, ErrUnknownPlayer)
+		}
+		message.Sender = sender.Name
+		message.SenderId = sender.Id
+
+		if !strings.HasPrefix(message.Target, "#") {
+			recipientId, ok := manager.names[message.Target]
+			if !ok {
+				return fmt.Errorf("player %s: %w", message.Target, ErrUnknownPlayer)
+			}
+			return deliver(outbox, manager.players[recipientId], message)
+		}
+
+		channel, ok := manager.channels[message.Target]
+		if !ok {
+			return fmt.Errorf("channel %s: %w", message.Target, ErrUnknownChannel)
+		}
+		if !channel.members[id] {
+			return fmt.Errorf("channel %s: %w", message.Target, ErrNotMember)
+		}
+
+		errs := make([]error, 0)
+		for _, member := range manager.members(channel) {
+			if member.Id != id {
+				errs = append(errs, deliver(outbox, member, message))
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Broadcast delivers a message to every member of a channel, e.g. from a bot
+func (manager *Manager) Broadcast(message chio.Message) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		channel, ok := manager.channels[message.Target]
+		if !ok {
+			return fmt.Errorf("channel %s: %w", message.Target, ErrUnknownChannel)
+		}
+
+		errs := make([]error, 0)
+		for _, member := range manager.members(channel) {
+			errs = append(errs, deliver(outbox, member, message))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// deliver writes a message to a recipient, moving it into #osu
+// for versions that can't display the target it was sent to
+func deliver(outbox *chio.Outbox, recipient *chio.Session, message chio.Message) error {
+	if !displaysTarget(recipient.IO, message.Target) {
+		message = chio.InlineTarget(message)
+	}
+	return recipient.IO.WriteMessage(outbox.Stream(recipient), message)
+}
+
+// displaysTarget reports whether a client can display messages sent to the target
+func displaysTarget(client chio.BanchoIO, target string) bool {
+	switch {
+	case target == DefaultChannel:
+		return true
+	case strings.HasPrefix(target, "#"):
+		return chio.Supports(client, chio.FeatureMessageTargets)
+	default:
+		return chio.Supports(client, chio.FeaturePrivateMessages)
+	}
+}
+
+// Channel returns a channel, including its current amount of members
+func (manager *Manager) Channel(name string) (chio.Channel, bool) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	channel, ok := manager.channels[name]
+	if !ok {
+		return chio.Channel{}, false
+	}
+	return channel.info(), true
+}
+
+// Channels returns every channel, ordered by name
+func (manager *Manager) Channels() []chio.Channel {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	channels := manager.sortedChannels()
+	infos := make([]chio.Channel, len(channels))
+	for i, channel := range channels {
+		infos[i] = channel.info()
+	}
+	return infos
+}
+
+// Members returns the ids of the members of a channel, in ascending order
+func (manager *Manager) Members(name string) []int32 {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	channel, ok := manager.channels[name]
+	if !ok {
+		return nil
+	}
+	return sortedIds(channel.members)
+}
+
+// broadcastInfo sends the current info of a channel to every player
+func (manager *Manager) broadcastInfo(outbox *chio.Outbox, channel *channel) error {
+	errs := make([]error, 0)
+	for _, player := range manager.sortedPlayers() {
+		errs = append(errs, player.IO.WriteChannelAvailable(outbox.Stream(player), channel.info()))
+	}
+	return errors.Join(errs...)
+}
+
+func (channel *channel) info() chio.Channel {
+	info := channel.Channel
+	info.UserCount = int16(len(channel.members))
+	return info
+}
+
+// members returns the members of a channel, ordered by id
+func (manager *Manager) members(channel *channel) []*chio.Session {
+	ids := sortedIds(channel.members)
+	players := make([]*chio.Session, 0, len(ids))
+	for _, id := range ids {
+		if player, ok := manager.players[id]; ok {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+func (manager *Manager) sortedPlayers() []*chio.Session {
+	players := make([]*chio.Session, 0, len(manager.players))
+	for _, player := range manager.players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].Id < players[j].Id })
+	return players
+}
+
+func (manager *Manager) sortedChannels() []*channel {
+	channels := make([]*channel, 0, len(manager.channels))
+	for _, channel := range manager.channels {
+		channels = append(channels, channel)
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i].Name < channels[j].Name })
+	return channels
+}
+
+func sortedIds(set map[int32]bool) []int32 {
+	ids := make([]int32, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
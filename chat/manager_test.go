@@ -0,0 +1,216 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/chiotest"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+// channelRecorder records the channel packets, which none of the versions in this package support
+type channelRecorder struct {
+	*clients.B323
+	events []string
+}
+
+func (recorder *channelRecorder) WriteChannelJoinSuccess(stream io.Writer, channel string) error {
+	recorder.events = append(recorder.events, "join "+channel)
+	return nil
+}
+
+func (recorder *channelRecorder) WriteChannelRevoked(stream io.Writer, channel string) error {
+	recorder.events = append(recorder.events, "revoked "+channel)
+	return nil
+}
+
+func (recorder *channelRecorder) WriteChannelAvailable(stream io.Writer, channel chio.Channel) error {
+	recorder.events = append(recorder.events, fmt.Sprintf("available %s %d", channel.Name, channel.UserCount))
+	return nil
+}
+
+func (recorder *channelRecorder) WriteChannelAvailableAutojoin(stream io.Writer, channel chio.Channel) error {
+	recorder.events = append(recorder.events, "autojoin "+channel.Name)
+	return nil
+}
+
+func (recorder *channelRecorder) WriteChannelInfoComplete(stream io.Writer) error {
+	recorder.events = append(recorder.events, "complete")
+	return nil
+}
+
+func (recorder *channelRecorder) expect(t *testing.T, events ...string) {
+	t.Helper()
+	if strings.Join(recorder.events, ", ") != strings.Join(events, ", ") {
+		t.Fatalf("expected events %q, got %q", events, recorder.events)
+	}
+	recorder.events = nil
+}
+
+func TestChannelMembership(t *testing.T) {
+	manager := NewManager()
+	recorder := &channelRecorder{B323: clients.NewB323()}
+	if err := manager.Add(chiotest.NewSession(1, recorder).Session); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t, "autojoin #osu", "complete", "join #osu", "available #osu 1")
+
+	if err := manager.Create(chio.Channel{Name: "#lobby", Topic: "Multiplayer"}, false); err != nil {
+		t.Fatal(err)
+	}
+	recorder.expect(t, "available #lobby 0")
+
+	if err := manager.Join(1, "#lobby"); err != nil {
+		t.Fatal(err)
+	}
+	recorder.expect(t, "join #lobby", "available #lobby 1")
+
+	if err := manager.Join(1, "#unknown"); !errors.Is(err, ErrUnknownChannel) {
+		t.Fatalf("expected ErrUnknownChannel, got %v", err)
+	}
+	recorder.expect(t, "revoked #unknown")
+
+	if err := manager.Close("#lobby"); err != nil {
+		t.Fatal(err)
+	}
+	recorder.expect(t, "revoked #lobby")
+
+	manager.Remove(1)
+	if channel, _ := manager.Channel(DefaultChannel); channel.UserCount != 0 {
+		t.Fatalf("expected #osu to be empty, got %d members", channel.UserCount)
+	}
+}
+
+func TestChannelDelivery(t *testing.T) {
+	manager := NewManager()
+	manager.Create(chio.Channel{Name: "#lobby"}, false)
+
+	manager.Add(chiotest.NewSession(1, clients.NewB323()).Session)
+	modern := chiotest.NewSession(2, clients.NewB320())
+	manager.Add(modern.Session)
+	old := chiotest.NewSession(3, clients.NewB294())
+	manager.Add(old.Session)
+
+	for _, id := range []int32{1, 2, 3} {
+		manager.Join(id, "#lobby")
+	}
+
+	if err := manager.Send(1, chio.Message{Content: "hi", Target: "#lobby"}); err != nil {
+		t.Fatal(err)
+	}
+
+	chiotest.ExpectWhere(t, modern.Stream, chio.BanchoSendMessage, func(message chio.Message) bool {
+		return message.Target == "#lobby" && message.Content == "hi" && message.Sender == "player1"
+	})
+	chiotest.ExpectWhere(t, old.Stream, chio.BanchoSendMessage, func(message chio.Message) bool {
+		return message.Target == DefaultChannel && message.Content == "(#lobby) hi"
+	})
+
+	if err := manager.Send(2, chio.Message{Content: "hello", Target: "player3"}); err != nil {
+		t.Fatal(err)
+	}
+	chiotest.ExpectWhere(t, old.Stream, chio.BanchoSendMessage, func(message chio.Message) bool {
+		return message.Target != DefaultChannel && message.Content == "hello" && message.Sender == "player2"
+	})
+
+	// b282 can't receive private messages, which are inlined even if its fallback would drop them
+	client := clients.NewB282()
+	client.OverrideFallback(chio.NewFallbackPolicy(chio.FallbackDrop))
+	oldest := chiotest.NewSession(4, client)
+	manager.Add(oldest.Session)
+
+	if err := manager.Send(2, chio.Message{Content: "hello", Target: "player4"}); err != nil {
+		t.Fatal(err)
+	}
+	chiotest.ExpectWhere(t, oldest.Stream, chio.BanchoSendMessage, func(message chio.Message) bool {
+		return message.Content == "(PM to player4) hello" && message.Sender == "player2"
+	})
+}
+
+func TestReplacePlayer(t *testing.T) {
+	manager := NewManager()
+	manager.Add(chiotest.NewSession(1, clients.NewB323()).Session)
+	manager.Add(chiotest.NewSession(2, clients.NewB323()).Session)
+
+	renamed := chiotest.NewSession(1, clients.NewB323())
+	renamed.Name = "renamed"
+	if err := manager.Add(renamed.Session); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Send(2, chio.Message{Content: "hi", Target: "player1"}); !errors.Is(err, ErrUnknownPlayer) {
+		t.Fatalf("expected the previous name to be removed, got %v", err)
+	}
+	if err := manager.Send(2, chio.Message{Content: "hi", Target: "renamed"}); err != nil {
+		t.Fatal(err)
+	}
+	chiotest.ExpectWhere(t, renamed.Stream, chio.BanchoSendMessage, func(message chio.Message) bool {
+		return message.Content == "hi" && message.Sender == "player2"
+	})
+}
+
+func TestReconnect(t *testing.T) {
+	manager := NewManager()
+	manager.Create(chio.Channel{Name: "#lobby"}, false)
+	manager.Add(chiotest.NewSession(1, clients.NewB323()).Session)
+	other := &channelRecorder{B323: clients.NewB323()}
+	manager.Add(chiotest.NewSession(2, other).Session)
+	manager.Join(1, "#lobby")
+	other.events = nil
+
+	reconnected := &channelRecorder{B323: clients.NewB323()}
+	if err := manager.Add(chiotest.NewSession(1, reconnected).Session); err != nil {
+		t.Fatal(err)
+	}
+
+	// Channels that are not joined automatically have to be joined again
+	reconnected.expect(t, "available #lobby 0", "autojoin #osu", "complete", "join #osu", "available #osu 2", "available #lobby 0")
+	other.expect(t, "available #osu 2", "available #lobby 0")
+
+	if members := manager.Members(DefaultChannel); len(members) != 2 {
+		t.Fatalf("expected 2 members in #osu, got %v", members)
+	}
+	if members := manager.Members("#lobby"); len(members) != 0 {
+		t.Fatalf("expected #lobby to be empty, got %v", members)
+	}
+}
+
+func TestHandleChannelPackets(t *testing.T) {
+	manager := NewManager()
+	manager.Create(chio.Channel{Name: "#lobby"}, false)
+	manager.Add(chiotest.NewSession(1, clients.NewB323()).Session)
+
+	join := &chio.BanchoPacket{Id: chio.OsuChannelJoin, Data: &chio.ChannelJoin{Name: "#lobby"}}
+	if err := manager.Handle(1, join); err != nil {
+		t.Fatal(err)
+	}
+	if members := manager.Members("#lobby"); len(members) != 1 || members[0] != 1 {
+		t.Fatalf("expected player 1 to join #lobby, got %v", members)
+	}
+
+	leave := &chio.BanchoPacket{Id: chio.OsuChannelLeave, Data: &chio.ChannelLeave{Name: "#lobby"}}
+	if err := manager.Handle(1, leave); err != nil {
+		t.Fatal(err)
+	}
+	if members := manager.Members("#lobby"); len(members) != 0 {
+		t.Fatalf("expected #lobby to be empty, got %v", members)
+	}
+}
+
+func TestChannelSendErrors(t *testing.T) {
+	manager := NewManager()
+	manager.Create(chio.Channel{Name: "#lobby"}, false)
+	manager.Add(chiotest.NewSession(1, clients.NewB323()).Session)
+
+	if err := manager.Send(1, chio.Message{Content: "hi", Target: "#lobby"}); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("expected ErrNotMember, got %v", err)
+	}
+	if err := manager.Send(1, chio.Message{Content: "hi", Target: "nobody"}); !errors.Is(err, ErrUnknownPlayer) {
+		t.Fatalf("expected ErrUnknownPlayer, got %v", err)
+	}
+}
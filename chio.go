@@ -1,10 +1,14 @@
 package chio
 
 import (
+	"errors"
 	"io"
-	"sort"
 )
 
+// ErrNotImplemented is returned by ReadPacket for packets that the client version
+// does not implement, and by writers if the fallback strategy asks for an error
+var ErrNotImplemented = errors.New("not implemented")
+
 // BanchoPacket is a struct that represents a packet that
 // is sent or received
 type BanchoPacket struct {
@@ -49,6 +53,27 @@ type BanchoIO interface {
 	BanchoHelpers
 }
 
+// FeatureIO is implemented by clients that declare their features. It is separate
+// from BanchoIO, so that existing implementations of BanchoIO keep working without it.
+// Use Supports & ClientFeatures to query the features of any BanchoIO.
+type FeatureIO interface {
+	// Supports checks if the client has a feature
+	Supports(feature Feature) bool
+
+	// Features returns every feature of the client
+	Features() FeatureSet
+}
+
+// ConfigurableIO is implemented by clients that allow changing how packets are written.
+// Like FeatureIO, it is optional for implementations of BanchoIO.
+type ConfigurableIO interface {
+	// OverrideFallback lets you specify how packets that the client doesn't support are handled
+	OverrideFallback(strategy FallbackStrategy)
+
+	// OverrideCompression lets you specify which packets are compressed, and with which gzip level
+	OverrideCompression(strategy CompressionStrategy)
+}
+
 // BanchoWriters is an interface that wraps the methods for writing
 // to a Bancho client
 type BanchoWriters interface {
@@ -112,59 +137,99 @@ type BanchoWriters interface {
 	WriteSwitchTournamentServer(stream io.Writer, ip string) error
 }
 
+// OsuIO is the client-side counterpart of BanchoIO. It writes packets
+// like an osu! client would, and reads the packets sent by bancho.
+type OsuIO interface {
+	// WritePacket writes a packet to the provided stream
+	WritePacket(stream io.Writer, packetId uint16, data []byte) error
+
+	// ReadPacket reads a packet that was sent by the server
+	ReadPacket(stream io.Reader) (packet *BanchoPacket, err error)
+
+	// ImplementsPacket checks if the packetId is implemented in the client
+	ImplementsPacket(packetId uint16) bool
+
+	// Supports checks if the client has a feature
+	Supports(feature Feature) bool
+
+	// ProtocolVersion returns the bancho protocol version used by the client
+	ProtocolVersion() int
+
+	// GetReaders returns the registry of readers for server packets
+	GetReaders() ReaderRegistry
+
+	// Packet writers
+	OsuWriters
+}
+
+// OsuWriters is an interface that wraps the methods for writing
+// to a Bancho server
+type OsuWriters interface {
+	WriteSendUserStatus(stream io.Writer, status UserStatus) error
+	WriteSendIrcMessage(stream io.Writer, message Message) error
+	WriteExit(stream io.Writer) error
+	WriteRequestStatusUpdate(stream io.Writer) error
+	WritePong(stream io.Writer) error
+	WriteStartSpectating(stream io.Writer, userId int32) error
+	WriteStopSpectating(stream io.Writer) error
+	WriteSpectateFrames(stream io.Writer, bundle ReplayFrameBundle) error
+	WriteErrorReport(stream io.Writer, text string) error
+	WriteCantSpectate(stream io.Writer) error
+	WriteSendIrcMessagePrivate(stream io.Writer, message Message) error
+	WriteLobbyPart(stream io.Writer) error
+	WriteLobbyJoin(stream io.Writer) error
+	WriteMatchCreate(stream io.Writer, match Match) error
+	WriteMatchJoin(stream io.Writer, join MatchJoin) error
+	WriteMatchPart(stream io.Writer) error
+	WriteMatchChangeSlot(stream io.Writer, slotId int32) error
+	WriteMatchReady(stream io.Writer) error
+	WriteMatchLock(stream io.Writer, slotId int32) error
+	WriteMatchChangeSettings(stream io.Writer, match Match) error
+	WriteMatchStart(stream io.Writer) error
+	WriteMatchScoreUpdate(stream io.Writer, frame ScoreFrame) error
+	WriteMatchComplete(stream io.Writer) error
+	WriteMatchChangeBeatmap(stream io.Writer, match Match) error
+}
+
 type BanchoHelpers interface {
 	ConvertInputPacketId(packetId uint16) uint16
 	ConvertOutputPacketId(packetId uint16) uint16
 	WriteMatch(match Match) []byte
 }
 
-var clients = make(map[int]BanchoIO)
-var sortedVersions []int
-
 // RegisterClient registers a client instance for a specific version
 // This is called by client implementations in their init() functions
 func RegisterClient(version int, client BanchoIO) {
-	clients[version] = client
+	DefaultRegistry.Register(version, client)
+}
 
-	sortedVersions = sortedVersions[:0]
-	for v := range clients {
-		sortedVersions = append(sortedVersions, v)
+// RegisterClientRange registers a client instance for a range of versions.
+// Like RegisterClient, it is meant to be called from init() functions, and
+// panics if the upper bound is below the version.
+func RegisterClientRange(version int, maxVersion int, client BanchoIO) {
+	if err := DefaultRegistry.RegisterRange(version, maxVersion, client); err != nil {
+		panic(err)
 	}
-
-	sort.Ints(sortedVersions)
 }
 
-// GetClientInterface returns a BanchoIO interface for the given client version
+// GetClientInterface returns a BanchoIO interface for the given client version.
+// It returns nil if the version can't be resolved with the policy of the default
+// registry, which will clamp it to the registered versions unless configured otherwise.
 func GetClientInterface(clientVersion int) BanchoIO {
-	if len(sortedVersions) == 0 {
-		return nil
-	}
-
-	lowestVersion := sortedVersions[0]
-	highestVersion := sortedVersions[len(sortedVersions)-1]
-
-	if clientVersion < lowestVersion {
-		return clients[lowestVersion]
-	}
-
-	if clientVersion > highestVersion {
-		return clients[highestVersion]
-	}
-
-	// Exact match
-	if client, ok := clients[clientVersion]; ok {
-		return client
-	}
+	return DefaultRegistry.GetClientInterface(clientVersion)
+}
 
-	// Find the highest version that is <= clientVersion
-	bestVersion := lowestVersion
-	for _, version := range sortedVersions {
-		if version <= clientVersion {
-			bestVersion = version
-		} else {
-			break
-		}
+// ResolveClientInterface returns a BanchoIO interface for the given client version,
+// or ErrUnsupportedVersion if the version is not supported with the provided policy
+func ResolveClientInterface(clientVersion int, policy ResolvePolicy) (BanchoIO, error) {
+	resolution, err := DefaultRegistry.ResolveWith(clientVersion, policy)
+	if err != nil {
+		return nil, err
 	}
+	return resolution.Client, nil
+}
 
-	return clients[bestVersion]
+// Versions returns every registered client version in ascending order
+func Versions() []int {
+	return DefaultRegistry.Versions()
 }
@@ -0,0 +1,81 @@
+package chiotest
+
+import (
+	"io"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+func newMatch(id int32) chio.Match {
+	match := chio.Match{Id: id, Name: "test"}
+	for i := 0; i < 8; i++ {
+		match.Slots = append(match.Slots, &chio.MatchSlot{Status: chio.SlotStatusOpen})
+	}
+	return match
+}
+
+func TestStreamExpect(t *testing.T) {
+	server := clients.NewB298()
+	stream := NewStream(server)
+
+	server.WritePing(stream)
+	server.WriteAnnouncement(stream, "Hello!")
+	server.WriteMatchJoinSuccess(stream, newMatch(3))
+	ExpectNoError(t, stream)
+
+	ExpectNext(t, stream, chio.BanchoPing)
+	announcement := ExpectData[chio.Announcement](t, stream, chio.BanchoAnnounce)
+	if announcement.Text != "Hello!" {
+		t.Fatalf("expected announcement 'Hello!', got %q", announcement.Text)
+	}
+
+	ExpectWhere(t, stream, chio.BanchoMatchJoinSuccess, func(match chio.Match) bool {
+		return match.Id == 3
+	})
+	ExpectNone(t, stream)
+}
+
+func TestClientScript(t *testing.T) {
+	server := clients.NewB294()
+	client := NewClient(server)
+
+	err := client.Script(
+		func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteSendIrcMessage(stream, chio.Message{Content: "hi", Target: "#osu"})
+		},
+		func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteStartSpectating(stream, 5)
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packets, err := client.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packets) != 2 {
+		t.Fatalf("expected 2 packets, got %d", len(packets))
+	}
+
+	spectating, err := chio.Decode[chio.StartSpectating](packets[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spectating.UserId != 5 {
+		t.Fatalf("expected user id 5, got %d", spectating.UserId)
+	}
+}
+
+func TestSession(t *testing.T) {
+	session := NewSession(2, clients.NewB323())
+	if session.Id != 2 || session.Name != "player2" {
+		t.Fatalf("unexpected session %+v", session.Session)
+	}
+
+	session.IO.WriteSpectatorJoined(session.Session.Stream, 1)
+	ExpectData[chio.SpectatorJoined](t, session.Stream, chio.BanchoSpectatorJoined)
+}
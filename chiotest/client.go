@@ -0,0 +1,98 @@
+package chiotest
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+// Step writes one or more packets as the client would, e.g.
+//
+//	func(osu chio.OsuIO, stream io.Writer) error {
+//		return osu.WriteSendIrcMessage(stream, chio.Message{Content: "hi", Target: "#osu"})
+//	}
+type Step func(osu chio.OsuIO, stream io.Writer) error
+
+// Client is a scripted fake osu! client. Packets that are written through
+// its OsuIO are queued, and can be read by the server through ReadPacket.
+type Client struct {
+	IO  chio.BanchoIO
+	Osu *clients.OsuClient
+
+	queue bytes.Buffer
+	mu    sync.Mutex
+}
+
+func NewClient(io chio.BanchoIO) *Client {
+	return &Client{IO: io, Osu: clients.NewOsuClient(io)}
+}
+
+// Script queues the packets of every step, in order
+func (client *Client) Script(steps ...Step) error {
+	for _, step := range steps {
+		if err := step(client.Osu, client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write queues raw data, which allows the client to be used as the stream of its OsuIO
+func (client *Client) Write(p []byte) (int, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.queue.Write(p)
+}
+
+// Read returns the queued data, or io.EOF once everything has been read
+func (client *Client) Read(p []byte) (int, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.queue.Read(p)
+}
+
+// Len returns the amount of bytes that were not read yet
+func (client *Client) Len() int {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.queue.Len()
+}
+
+// ReadAll reads every queued packet through ReadPacket of the server
+func (client *Client) ReadAll() ([]*chio.BanchoPacket, error) {
+	packets := make([]*chio.BanchoPacket, 0)
+
+	for client.Len() > 0 {
+		packet, err := client.IO.ReadPacket(client)
+		if err != nil {
+			return packets, err
+		}
+		packets = append(packets, packet)
+	}
+
+	return packets, nil
+}
+
+// Conn is an in-memory connection for handlers that read & write on the same stream.
+// Reads are served by the scripted client, and writes are decoded by the stream.
+type Conn struct {
+	*Client
+	Stream *Stream
+}
+
+func NewConn(io chio.BanchoIO) *Conn {
+	return &Conn{Client: NewClient(io), Stream: NewStream(io)}
+}
+
+// Write decodes packets written by the server
+func (conn *Conn) Write(p []byte) (int, error) {
+	return conn.Stream.Write(p)
+}
+
+// Close does nothing, and only exists so that the connection can be passed as an io.ReadWriteCloser
+func (conn *Conn) Close() error {
+	return nil
+}
@@ -0,0 +1,118 @@
+package chiotest
+
+import (
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+// Expect consumes packets until one with the packet id is found,
+// and fails the test if there is none
+func Expect(t testing.TB, stream *Stream, packetId uint16) *chio.BanchoPacket {
+	t.Helper()
+
+	packet := stream.find(func(packet *chio.BanchoPacket) bool {
+		return packet.Id == packetId
+	})
+	if packet == nil {
+		t.Fatalf("expected %s, got %s", chio.PacketName(packetId), describe(stream.Pending()))
+	}
+	return packet
+}
+
+// ExpectNext consumes the next packet, and fails the test if it doesn't have the packet id
+func ExpectNext(t testing.TB, stream *Stream, packetId uint16) *chio.BanchoPacket {
+	t.Helper()
+
+	packet := stream.Next()
+	if packet == nil {
+		t.Fatalf("expected %s, got no packet", chio.PacketName(packetId))
+	}
+	if packet.Id != packetId {
+		t.Fatalf("expected %s, got %s", chio.PacketName(packetId), packet)
+	}
+	return packet
+}
+
+// ExpectData consumes packets until one with the packet id is found, and returns its data
+func ExpectData[T any](t testing.TB, stream *Stream, packetId uint16) T {
+	t.Helper()
+
+	data, err := chio.Decode[T](Expect(t, stream, packetId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// ExpectWhere consumes packets until one with the packet id is found, for which match
+// returns true, e.g. a BanchoMatchJoinSuccess with a specific match id:
+//
+//	chiotest.ExpectWhere(t, stream, chio.BanchoMatchJoinSuccess, func(match chio.Match) bool {
+//		return match.Id == 3
+//	})
+func ExpectWhere[T any](t testing.TB, stream *Stream, packetId uint16, match func(T) bool) T {
+	t.Helper()
+
+	var result T
+	packet := stream.find(func(packet *chio.BanchoPacket) bool {
+		if packet.Id != packetId {
+			return false
+		}
+		data, err := chio.Decode[T](packet)
+		if err != nil || !match(data) {
+			return false
+		}
+		result = data
+		return true
+	})
+	if packet == nil {
+		t.Fatalf("expected %s matching the condition, got %s", chio.PacketName(packetId), describe(stream.Pending()))
+	}
+	return result
+}
+
+// ExpectNone fails the test if there are packets left that were not consumed
+func ExpectNone(t testing.TB, stream *Stream) {
+	t.Helper()
+
+	if pending := stream.Pending(); len(pending) > 0 {
+		t.Fatalf("expected no more packets, got %s", describe(pending))
+	}
+}
+
+// ExpectNoPacket fails the test if any of the remaining packets has the packet id
+func ExpectNoPacket(t testing.TB, stream *Stream, packetId uint16) {
+	t.Helper()
+
+	for _, packet := range stream.Pending() {
+		if packet.Id == packetId {
+			t.Fatalf("expected no %s, got %s", chio.PacketName(packetId), packet)
+		}
+	}
+}
+
+// ExpectNoError fails the test if any packet of the stream could not be decoded
+func ExpectNoError(t testing.TB, stream *Stream) {
+	t.Helper()
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("failed to decode stream: %v", err)
+	}
+}
+
+// describe returns a short summary of packets for failure messages
+func describe(packets []*chio.BanchoPacket) string {
+	if len(packets) == 0 {
+		return "no packets"
+	}
+
+	names := ""
+	for i, packet := range packets {
+		if i > 0 {
+			names += ", "
+		}
+		names += chio.PacketName(packet.Id)
+	}
+	return names
+}
@@ -0,0 +1,23 @@
+package chiotest
+
+import (
+	"fmt"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+// Session is a player named after its id, e.g. "player2", whose
+// packets are decoded by the stream. It can be added to any of the
+// spectator, multiplayer, chat & presence managers through its chio.Session.
+type Session struct {
+	*chio.Session
+	Stream *Stream
+}
+
+func NewSession(id int32, io chio.BanchoIO) *Session {
+	stream := NewStream(io)
+	return &Session{
+		Session: &chio.Session{Id: id, Name: fmt.Sprintf("player%d", id), IO: io, Stream: stream},
+		Stream:  stream,
+	}
+}
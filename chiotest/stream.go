@@ -0,0 +1,128 @@
+// Package chiotest provides helpers for testing servers that are built on chio.
+//
+// A Stream decodes everything that a server writes through a BanchoIO back into
+// typed packets, which can then be checked with the Expect functions. A Client
+// does the opposite, and feeds Osu* packets into the ReadPacket of the server.
+package chiotest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+	"github.com/Lekuruu/chio-go/internal"
+)
+
+// Stream is an in-memory io.Writer, that decodes every packet written
+// to it with the client side of the provided BanchoIO
+type Stream struct {
+	IO  chio.BanchoIO
+	Osu *clients.OsuClient
+
+	pending internal.FrameBuffer
+	packets []*chio.BanchoPacket
+	cursor  int
+	errors  []error
+	mu      sync.Mutex
+}
+
+func NewStream(io chio.BanchoIO) *Stream {
+	return &Stream{IO: io, Osu: clients.NewOsuClient(io)}
+}
+
+// Write decodes every packet that was completed by p
+func (stream *Stream) Write(p []byte) (int, error) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	stream.pending.Write(p)
+
+	for {
+		frame, err := stream.pending.Next()
+		if err != nil {
+			stream.errors = append(stream.errors, err)
+			break
+		}
+		if frame == nil {
+			break
+		}
+
+		packet, err := stream.Osu.ReadPacket(bytes.NewReader(frame))
+		if err != nil {
+			stream.errors = append(stream.errors, err)
+			continue
+		}
+		stream.packets = append(stream.packets, packet)
+	}
+
+	return len(p), nil
+}
+
+// Packets returns every packet that was written to the stream
+func (stream *Stream) Packets() []*chio.BanchoPacket {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	return append([]*chio.BanchoPacket(nil), stream.packets...)
+}
+
+// Pending returns the packets that were not consumed by Next or the Expect functions yet
+func (stream *Stream) Pending() []*chio.BanchoPacket {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	return append([]*chio.BanchoPacket(nil), stream.packets[stream.cursor:]...)
+}
+
+// Next consumes the next packet, or returns nil if there is none
+func (stream *Stream) Next() *chio.BanchoPacket {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if stream.cursor >= len(stream.packets) {
+		return nil
+	}
+
+	packet := stream.packets[stream.cursor]
+	stream.cursor++
+	return packet
+}
+
+// Reset discards every packet and error of the stream
+func (stream *Stream) Reset() {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	stream.pending.Reset()
+	stream.packets = nil
+	stream.errors = nil
+	stream.cursor = 0
+}
+
+// Err returns the errors that occurred while decoding packets,
+// as well as an error for an incomplete packet at the end of the stream
+func (stream *Stream) Err() error {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	errs := append([]error(nil), stream.errors...)
+	if stream.pending.Len() > 0 {
+		errs = append(errs, fmt.Errorf("incomplete packet with %d bytes at the end of the stream", stream.pending.Len()))
+	}
+	return errors.Join(errs...)
+}
+
+// find consumes every packet up to and including the first one that matches
+func (stream *Stream) find(match func(*chio.BanchoPacket) bool) *chio.BanchoPacket {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	for i := stream.cursor; i < len(stream.packets); i++ {
+		if match(stream.packets[i]) {
+			stream.cursor = i + 1
+			return stream.packets[i]
+		}
+	}
+	return nil
+}
@@ -2,8 +2,11 @@ package clients
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	chio "github.com/Lekuruu/chio-go"
 	"github.com/Lekuruu/chio-go/internal"
@@ -13,77 +16,113 @@ import (
 // Every following version will be based on it.
 type B282 struct {
 	chio.BanchoIO
-	SupportedPacketIds []uint16
-	ProtocolVer        int
-	SlotSize           int
-	Readers            chio.ReaderRegistry
-	Instance           chio.BanchoIO // Reference to the outermost client type for dispatch
+	Packets     *chio.PacketTable
+	ProtocolVer int
+	SlotSize    int
+	Readers     chio.ReaderRegistry
+	Instance    chio.BanchoIO // Reference to the outermost client type for dispatch
+
+	// Features that depend on the layout of packets, which
+	// can't be derived from the packet table
+	LayoutFeatures chio.FeatureSet
+
+	// Decides how packets are handled, that are not supported by the client
+	Fallback chio.FallbackStrategy
+
+	// Decides which packets are compressed
+	Compression chio.CompressionStrategy
 }
 
 func (client *B282) WritePacket(stream io.Writer, packetId uint16, data []byte) error {
+	compress := client.Compression.Compresses(packetId, len(data))
+
 	// Convert packetId back for the client
 	packetId = client.Instance.ConvertOutputPacketId(packetId)
 
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
+
 	err := internal.WriteUint16(writer, packetId)
 	if err != nil {
 		return err
 	}
 
-	compressed := internal.CompressData(data)
-	err = internal.WriteUint32(writer, uint32(len(compressed)))
+	// Reserve space for the length, which is known after compression
+	err = internal.WriteUint32(writer, 0)
 	if err != nil {
 		return err
 	}
 
-	_, err = writer.Write(compressed)
+	if compress {
+		err = internal.CompressLevelTo(writer, data, client.Compression.Level())
+	} else {
+		_, err = writer.Write(data)
+	}
 	if err != nil {
 		return err
 	}
 
-	_, err = stream.Write(writer.Bytes())
+	frame := writer.Bytes()
+	binary.LittleEndian.PutUint32(frame[2:6], uint32(len(frame)-6))
+
+	_, err = stream.Write(frame)
 	return err
 }
 
 func (client *B282) ReadPacket(stream io.Reader) (packet *chio.BanchoPacket, err error) {
-	packet = &chio.BanchoPacket{}
-	packet.Id, err = internal.ReadUint16(stream)
+	return client.readPacket(stream, client.Readers)
+}
+
+// readPacket reads a packet from the stream, and decodes it with the provided readers
+func (client *B282) readPacket(stream io.Reader, readers chio.ReaderRegistry) (packet *chio.BanchoPacket, err error) {
+	wireId, err := internal.ReadUint16(stream)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert packet ID to a usable value
-	packet.Id = client.Instance.ConvertInputPacketId(packet.Id)
-
-	if !client.ImplementsPacket(packet.Id) {
-		return nil, fmt.Errorf("packet '%d' not implemented", packet.Id)
-	}
-
 	length, err := internal.ReadInt32(stream)
 	if err != nil {
 		return nil, err
 	}
+	if length < 0 || length > internal.MaxPacketSize {
+		return nil, fmt.Errorf("invalid length %d for packet '%d'", length, wireId)
+	}
 
-	compressedData := make([]byte, length)
-	n, err := stream.Read(compressedData)
+	compressed := internal.GetBuffer()
+	defer internal.PutBuffer(compressed)
+
+	_, err = io.CopyN(compressed, stream, int64(length))
+	if err == io.EOF {
+		return nil, fmt.Errorf("expected %d bytes, got %d", length, compressed.Len())
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if n != int(length) {
-		return nil, fmt.Errorf("expected %d bytes, got %d", length, n)
+	// The payload is consumed either way, so that
+	// the stream stays usable for the next packet
+	packetId, ok := client.Packets.PacketId(wireId)
+	if !ok {
+		return nil, fmt.Errorf("packet '%d' %w", wireId, chio.ErrNotImplemented)
 	}
+	packet = &chio.BanchoPacket{Id: packetId}
 
-	data, err := internal.DecompressData(compressedData)
-	if err != nil {
+	data := internal.GetBuffer()
+	defer internal.PutBuffer(data)
+
+	// Unless every payload is compressed, the gzip header tells them apart
+	payload := compressed.Bytes()
+	if client.Compression.Mode(packetId) != chio.CompressAlways && !internal.IsCompressed(payload) {
+		data.Write(payload)
+	} else if err = internal.DecompressTo(data, payload); err != nil {
 		return nil, err
 	}
 
-	reader, ok := client.Readers[packet.Id]
+	reader, ok := readers[packet.Id]
 	packet.Data = nil
 
 	if ok {
-		packet.Data, err = reader(client.Instance, bytes.NewReader(data))
+		packet.Data, err = reader(client.Instance, bytes.NewReader(data.Bytes()))
 		if err != nil {
 			return nil, err
 		}
@@ -93,16 +132,19 @@ func (client *B282) ReadPacket(stream io.Reader) (packet *chio.BanchoPacket, err
 }
 
 func (client *B282) SupportedPackets() []uint16 {
-	return client.SupportedPacketIds
+	return client.Packets.Packets()
 }
 
 func (client *B282) ImplementsPacket(packetId uint16) bool {
-	for _, id := range client.SupportedPackets() {
-		if id == packetId {
-			return true
-		}
-	}
-	return false
+	return client.Packets.Contains(packetId)
+}
+
+func (client *B282) Supports(feature chio.Feature) bool {
+	return client.Features().Has(feature)
+}
+
+func (client *B282) Features() chio.FeatureSet {
+	return chio.PacketFeatures(client.Packets) | client.LayoutFeatures
 }
 
 func (client *B282) ProtocolVersion() int {
@@ -121,30 +163,52 @@ func (client *B282) OverrideMatchSlotSize(amount int) {
 	client.SlotSize = amount
 }
 
-func (client *B282) ConvertInputPacketId(packetId uint16) uint16 {
-	if packetId == 11 {
-		// "IrcJoin" packet
-		return chio.BanchoHandleIrcJoin
-	}
-	if packetId > 11 && packetId <= 45 {
-		packetId -= 1
+func (client *B282) OverrideFallback(strategy chio.FallbackStrategy) {
+	client.Fallback = strategy
+}
+
+func (client *B282) OverrideCompression(strategy chio.CompressionStrategy) {
+	client.Compression = strategy
+}
+
+// unsupported handles a packet that the client doesn't support, according to its fallback strategy.
+// The emulate function is called in FallbackEmulate mode, and may be nil if there is no replacement.
+func (client *B282) unsupported(packetId uint16, emulate func() error) error {
+	switch client.Fallback.Mode(packetId) {
+	case chio.FallbackEmulate:
+		if emulate == nil {
+			return nil
+		}
+		return emulate()
+	case chio.FallbackError:
+		return fmt.Errorf("packet '%s' %w", chio.PacketName(packetId), chio.ErrNotImplemented)
 	}
-	if packetId > 50 {
-		packetId -= 1
+	return nil
+}
+
+// writeBotMessage writes a message from the fallback bot into #osu
+func (client *B282) writeBotMessage(stream io.Writer, content string) error {
+	return client.Instance.WriteMessage(stream, chio.Message{
+		Sender:  client.Fallback.BotName(),
+		Content: content,
+		Target:  "#osu",
+	})
+}
+
+// ConvertInputPacketId converts a wire id to a packet id.
+// Ids that are not part of the packet table are returned as they are.
+func (client *B282) ConvertInputPacketId(packetId uint16) uint16 {
+	if id, ok := client.Packets.PacketId(packetId); ok {
+		return id
 	}
 	return packetId
 }
 
+// ConvertOutputPacketId converts a packet id to a wire id.
+// Ids that are not part of the packet table are returned as they are.
 func (client *B282) ConvertOutputPacketId(packetId uint16) uint16 {
-	if packetId == chio.BanchoHandleIrcJoin {
-		// "IrcJoin" packet
-		return 11
-	}
-	if packetId >= 11 && packetId < 45 {
-		return packetId + 1
-	}
-	if packetId > 50 {
-		packetId += 1
+	if id, ok := client.Packets.WireId(packetId); ok {
+		return id
 	}
 	return packetId
 }
@@ -154,7 +218,8 @@ func (client *B282) GetReaders() chio.ReaderRegistry {
 }
 
 func (client *B282) WriteLoginReply(stream io.Writer, reply int32) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteInt32(writer, reply)
 	return client.WritePacket(stream, chio.BanchoLoginReply, writer.Bytes())
 }
@@ -162,27 +227,37 @@ func (client *B282) WriteLoginReply(stream io.Writer, reply int32) error {
 func (client *B282) WriteMessage(stream io.Writer, message chio.Message) error {
 	if message.Target != "#osu" {
 		// Private messages & channels have not been implemented yet
-		return nil
+		return client.unsupported(chio.BanchoSendMessage, func() error {
+			return client.writeTargetedMessage(stream, message)
+		})
 	}
 
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteString(writer, message.Sender)
 	internal.WriteString(writer, message.Content)
 	return client.WritePacket(stream, chio.BanchoSendMessage, writer.Bytes())
 }
 
+// writeTargetedMessage writes a message into #osu, with its original target as a prefix
+func (client *B282) writeTargetedMessage(stream io.Writer, message chio.Message) error {
+	return client.WriteMessage(stream, chio.InlineTarget(message))
+}
+
 func (client *B282) WritePing(stream io.Writer) error {
 	return client.WritePacket(stream, chio.BanchoPing, []byte{})
 }
 
 func (client *B282) WriteIrcChangeUsername(stream io.Writer, oldName string, newName string) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteString(writer, fmt.Sprintf("%s>>>>%s", oldName, newName))
 	return client.WritePacket(stream, chio.BanchoHandleIrcChangeUsername, writer.Bytes())
 }
 
 func (client *B282) WriteUserStats(stream io.Writer, info chio.UserInfo) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 
 	if info.Presence.IsIrc {
 		internal.WriteString(writer, info.Name)
@@ -194,7 +269,8 @@ func (client *B282) WriteUserStats(stream io.Writer, info chio.UserInfo) error {
 }
 
 func (client *B282) WriteUserQuit(stream io.Writer, quit chio.UserQuit) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 
 	if quit.Info.Presence.IsIrc && quit.QuitState != chio.QuitStateIrcRemaining {
 		internal.WriteString(writer, quit.Info.Name)
@@ -210,19 +286,27 @@ func (client *B282) WriteUserQuit(stream io.Writer, quit chio.UserQuit) error {
 }
 
 func (client *B282) WriteSpectatorJoined(stream io.Writer, userId int32) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteInt32(writer, userId)
 	return client.WritePacket(stream, chio.BanchoSpectatorJoined, writer.Bytes())
 }
 
 func (client *B282) WriteSpectatorLeft(stream io.Writer, userId int32) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteInt32(writer, userId)
 	return client.WritePacket(stream, chio.BanchoSpectatorLeft, writer.Bytes())
 }
 
 func (client *B282) WriteSpectateFrames(stream io.Writer, bundle chio.ReplayFrameBundle) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
+	client.WriteFrameBundle(writer, bundle)
+	return client.WritePacket(stream, chio.BanchoSpectateFrames, writer.Bytes())
+}
+
+func (client *B282) WriteFrameBundle(writer io.Writer, bundle chio.ReplayFrameBundle) error {
 	internal.WriteUint16(writer, uint16(len(bundle.Frames)))
 
 	for _, frame := range bundle.Frames {
@@ -238,7 +322,7 @@ func (client *B282) WriteSpectateFrames(stream io.Writer, bundle chio.ReplayFram
 	}
 
 	internal.WriteUint8(writer, bundle.Action)
-	return client.WritePacket(stream, chio.BanchoSpectateFrames, writer.Bytes())
+	return nil
 }
 
 func (client *B282) WriteVersionUpdate(stream io.Writer) error {
@@ -246,7 +330,8 @@ func (client *B282) WriteVersionUpdate(stream io.Writer) error {
 }
 
 func (client *B282) WriteSpectatorCantSpectate(stream io.Writer, userId int32) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteInt32(writer, userId)
 	return client.WritePacket(stream, chio.BanchoSpectatorCantSpectate, writer.Bytes())
 }
@@ -287,18 +372,19 @@ func (client *B282) WriteStats(writer io.Writer, info chio.UserInfo) error {
 	return nil
 }
 
-// Redirect UserPresence packets to UserStats
+// Redirect UserPresence packets to UserStats. The writers are dispatched
+// through the instance, since later versions override them.
 func (client *B282) WriteUserPresence(stream io.Writer, info chio.UserInfo) error {
-	return client.WriteUserStats(stream, info)
+	return client.Instance.WriteUserStats(stream, info)
 }
 
 func (client *B282) WriteUserPresenceSingle(stream io.Writer, info chio.UserInfo) error {
-	return client.WriteUserPresence(stream, info)
+	return client.Instance.WriteUserPresence(stream, info)
 }
 
 func (client *B282) WriteUserPresenceBundle(stream io.Writer, infos []chio.UserInfo) error {
 	for _, info := range infos {
-		err := client.WriteUserPresence(stream, info)
+		err := client.Instance.WriteUserPresence(stream, info)
 		if err != nil {
 			return err
 		}
@@ -406,106 +492,364 @@ func (client *B282) ReadReplayFrame(reader io.Reader) (*chio.ReplayFrame, error)
 	return frame, nil
 }
 
+func (client *B282) WriteIrcMessage(writer io.Writer, message chio.Message) error {
+	// Private messages & channels have not been implemented yet
+	return internal.WriteString(writer, message.Content)
+}
+
+func (client *B282) ReadBanchoMessage(reader io.Reader) (*chio.Message, error) {
+	sender, err := internal.ReadString(reader)
+	if err != nil {
+		return nil, err
+	}
+	content, err := internal.ReadString(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chio.Message{Sender: sender, Content: content, Target: "#osu"}, nil
+}
+
+func (client *B282) ReadStats(reader io.Reader) (*chio.UserInfo, error) {
+	var err error
+	info := &chio.UserInfo{Stats: &chio.UserStats{}}
+
+	info.Id, err = internal.ReadInt32(reader)
+	if err != nil {
+		return nil, err
+	}
+	info.Name, err = internal.ReadString(reader)
+	if err != nil {
+		return nil, err
+	}
+	info.Stats.Rscore, err = internal.ReadUint64(reader)
+	if err != nil {
+		return nil, err
+	}
+	info.Stats.Accuracy, err = internal.ReadFloat64(reader)
+	if err != nil {
+		return nil, err
+	}
+	info.Stats.Playcount, err = internal.ReadInt32(reader)
+	if err != nil {
+		return nil, err
+	}
+	info.Stats.Tscore, err = internal.ReadUint64(reader)
+	if err != nil {
+		return nil, err
+	}
+	info.Stats.Rank, err = internal.ReadInt32(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Avatar filename, which is derived from the user id
+	_, err = internal.ReadString(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	info.Status, err = client.ReadStatus(reader)
+	if err != nil {
+		return nil, err
+	}
+	info.Status.UpdateStats = info.Status.Action == chio.StatusStatsUpdate
+
+	info.Presence, err = client.ReadPresence(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// ReadPresence reads the timezone & location that are written at the end of the user stats
+func (client *B282) ReadPresence(reader io.Reader) (*chio.UserPresence, error) {
+	timezone, err := internal.ReadUint8(reader)
+	if err != nil {
+		return nil, err
+	}
+	location, err := internal.ReadString(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	presence := &chio.UserPresence{Timezone: int8(timezone) - 24}
+	country, city, _ := strings.Cut(location, " / ")
+	presence.City = city
+
+	for index, name := range chio.CountryNames {
+		if name == country {
+			presence.CountryIndex = int8(index)
+			break
+		}
+	}
+
+	return presence, nil
+}
+
+func (client *B282) ReadUserStats(reader io.Reader) (*chio.UserInfo, error) {
+	return client.ReadStats(reader)
+}
+
+func (client *B282) ReadUserQuit(reader io.Reader) (*chio.UserQuit, error) {
+	info, err := client.ReadStats(reader)
+	if err != nil {
+		return nil, err
+	}
+	return &chio.UserQuit{Info: info, QuitState: chio.QuitStateGone}, nil
+}
+
 func NewB282() *B282 {
 	client := &B282{
 		SlotSize:    8,
 		ProtocolVer: 0,
 		Readers:     make(chio.ReaderRegistry),
+		Fallback:    chio.DefaultFallback,
+		Compression: chio.DefaultCompression,
 	}
 	client.Instance = client
 
 	client.Readers[chio.OsuSendUserStatus] = internal.ReaderReadStatus()
 	client.Readers[chio.OsuSendIrcMessage] = internal.ReaderReadMessage()
-	client.Readers[chio.OsuStartSpectating] = internal.ReaderReadBanchoInt()
+	client.Readers[chio.OsuStartSpectating] = internal.ReaderReadStartSpectating()
 	client.Readers[chio.OsuSpectateFrames] = internal.ReaderReadFrameBundle()
-	client.Readers[chio.OsuErrorReport] = internal.ReaderReadBanchoString()
-
-	client.SupportedPacketIds = []uint16{
-		chio.OsuSendUserStatus,
-		chio.OsuSendIrcMessage,
-		chio.OsuExit,
-		chio.OsuRequestStatusUpdate,
-		chio.OsuPong,
-		chio.BanchoLoginReply,
-		chio.BanchoCommandError,
-		chio.BanchoSendMessage,
-		chio.BanchoPing,
-		chio.BanchoHandleIrcChangeUsername,
-		chio.BanchoHandleIrcQuit,
-		chio.BanchoHandleOsuUpdate,
-		chio.BanchoHandleOsuQuit,
-		chio.BanchoSpectatorJoined,
-		chio.BanchoSpectatorLeft,
-		chio.BanchoSpectateFrames,
-		chio.OsuStartSpectating,
-		chio.OsuStopSpectating,
-		chio.OsuSpectateFrames,
-		chio.BanchoVersionUpdate,
-		chio.OsuErrorReport,
-		chio.OsuCantSpectate,
-		chio.BanchoSpectatorCantSpectate,
-	}
+	client.Readers[chio.OsuErrorReport] = internal.ReaderReadErrorReport()
+
+	// Every packet from "BanchoHandleOsuUpdate" onwards is shifted
+	// by one, since "IrcJoin" used to take its place on the wire
+	client.Packets = chio.NewPacketTable(map[uint16]uint16{
+		chio.OsuSendUserStatus:             0,
+		chio.OsuSendIrcMessage:             1,
+		chio.OsuExit:                       2,
+		chio.OsuRequestStatusUpdate:        3,
+		chio.OsuPong:                       4,
+		chio.BanchoLoginReply:              5,
+		chio.BanchoCommandError:            6,
+		chio.BanchoSendMessage:             7,
+		chio.BanchoPing:                    8,
+		chio.BanchoHandleIrcChangeUsername: 9,
+		chio.BanchoHandleIrcQuit:           10,
+		chio.BanchoHandleIrcJoin:           11,
+		chio.BanchoHandleOsuUpdate:         12,
+		chio.BanchoHandleOsuQuit:           13,
+		chio.BanchoSpectatorJoined:         14,
+		chio.BanchoSpectatorLeft:           15,
+		chio.BanchoSpectateFrames:          16,
+		chio.OsuStartSpectating:            17,
+		chio.OsuStopSpectating:             18,
+		chio.OsuSpectateFrames:             19,
+		chio.BanchoVersionUpdate:           20,
+		chio.OsuErrorReport:                21,
+		chio.OsuCantSpectate:               22,
+		chio.BanchoSpectatorCantSpectate:   23,
+	})
 
 	return client
 }
 
+// New creates a new instance of b282, without the overrides of this one
+func (client *B282) New() chio.BanchoIO {
+	return NewB282()
+}
+
 func init() {
 	client := NewB282()
-	chio.RegisterClient(282, client)
-	chio.RegisterClient(290, client)
+	chio.RegisterClientRange(282, 289, client)
+	chio.RegisterClientRange(290, 290, client)
 }
 
 /* Unsupported Packets */
 
-func (client *B282) WriteGetAttention(stream io.Writer) error                        { return nil }
-func (client *B282) WriteAnnouncement(stream io.Writer, message string) error        { return nil }
-func (client *B282) WriteMatchUpdate(stream io.Writer, match chio.Match) error       { return nil }
-func (client *B282) WriteMatchNew(stream io.Writer, match chio.Match) error          { return nil }
-func (client *B282) WriteMatchDisband(stream io.Writer, matchId int32) error         { return nil }
-func (client *B282) WriteLobbyJoin(stream io.Writer, userId int32) error             { return nil }
-func (client *B282) WriteLobbyPart(stream io.Writer, userId int32) error             { return nil }
-func (client *B282) WriteMatchJoinSuccess(stream io.Writer, match chio.Match) error  { return nil }
-func (client *B282) WriteMatchJoinFail(stream io.Writer) error                       { return nil }
-func (client *B282) WriteFellowSpectatorJoined(stream io.Writer, userId int32) error { return nil }
-func (client *B282) WriteFellowSpectatorLeft(stream io.Writer, userId int32) error   { return nil }
-func (client *B282) WriteMatchStart(stream io.Writer, match chio.Match) error        { return nil }
+func (client *B282) WriteGetAttention(stream io.Writer) error {
+	return client.unsupported(chio.BanchoGetAttention, nil)
+}
+
+func (client *B282) WriteAnnouncement(stream io.Writer, message string) error {
+	return client.unsupported(chio.BanchoAnnounce, func() error {
+		return client.writeBotMessage(stream, message)
+	})
+}
+
+func (client *B282) WriteMatchUpdate(stream io.Writer, match chio.Match) error {
+	return client.unsupported(chio.BanchoMatchUpdate, nil)
+}
+
+func (client *B282) WriteMatchNew(stream io.Writer, match chio.Match) error {
+	return client.unsupported(chio.BanchoMatchNew, nil)
+}
+
+func (client *B282) WriteMatchDisband(stream io.Writer, matchId int32) error {
+	return client.unsupported(chio.BanchoMatchDisband, nil)
+}
+
+func (client *B282) WriteLobbyJoin(stream io.Writer, userId int32) error {
+	return client.unsupported(chio.BanchoLobbyJoin, nil)
+}
+
+func (client *B282) WriteLobbyPart(stream io.Writer, userId int32) error {
+	return client.unsupported(chio.BanchoLobbyPart, nil)
+}
+
+func (client *B282) WriteMatchJoinSuccess(stream io.Writer, match chio.Match) error {
+	return client.unsupported(chio.BanchoMatchJoinSuccess, nil)
+}
+
+func (client *B282) WriteMatchJoinFail(stream io.Writer) error {
+	return client.unsupported(chio.BanchoMatchJoinFail, nil)
+}
+
+func (client *B282) WriteFellowSpectatorJoined(stream io.Writer, userId int32) error {
+	return client.unsupported(chio.BanchoFellowSpectatorJoined, nil)
+}
+
+func (client *B282) WriteFellowSpectatorLeft(stream io.Writer, userId int32) error {
+	return client.unsupported(chio.BanchoFellowSpectatorLeft, nil)
+}
+
+func (client *B282) WriteMatchStart(stream io.Writer, match chio.Match) error {
+	return client.unsupported(chio.BanchoMatchStart, nil)
+}
+
 func (client *B282) WriteMatchScoreUpdate(stream io.Writer, frame chio.ScoreFrame) error {
-	return nil
+	return client.unsupported(chio.BanchoMatchScoreUpdate, nil)
+}
+
+func (client *B282) WriteMatchTransferHost(stream io.Writer) error {
+	return client.unsupported(chio.BanchoMatchTransferHost, nil)
+}
+
+func (client *B282) WriteMatchAllPlayersLoaded(stream io.Writer) error {
+	return client.unsupported(chio.BanchoMatchAllPlayersLoaded, nil)
+}
+
+func (client *B282) WriteMatchPlayerFailed(stream io.Writer, slotId uint32) error {
+	return client.unsupported(chio.BanchoMatchPlayerFailed, nil)
+}
+
+func (client *B282) WriteMatchComplete(stream io.Writer) error {
+	return client.unsupported(chio.BanchoMatchComplete, nil)
+}
+
+func (client *B282) WriteMatchSkip(stream io.Writer) error {
+	return client.unsupported(chio.BanchoMatchSkip, nil)
+}
+
+func (client *B282) WriteUnauthorized(stream io.Writer) error {
+	return client.unsupported(chio.BanchoUnauthorized, nil)
 }
-func (client *B282) WriteMatchTransferHost(stream io.Writer) error                  { return nil }
-func (client *B282) WriteMatchAllPlayersLoaded(stream io.Writer) error              { return nil }
-func (client *B282) WriteMatchPlayerFailed(stream io.Writer, slotId uint32) error   { return nil }
-func (client *B282) WriteMatchComplete(stream io.Writer) error                      { return nil }
-func (client *B282) WriteMatchSkip(stream io.Writer) error                          { return nil }
-func (client *B282) WriteUnauthorized(stream io.Writer) error                       { return nil }
-func (client *B282) WriteChannelJoinSuccess(stream io.Writer, channel string) error { return nil }
-func (client *B282) WriteChannelRevoked(stream io.Writer, channel string) error     { return nil }
+
+func (client *B282) WriteChannelJoinSuccess(stream io.Writer, channel string) error {
+	return client.unsupported(chio.BanchoChannelJoinSuccess, nil)
+}
+
+func (client *B282) WriteChannelRevoked(stream io.Writer, channel string) error {
+	return client.unsupported(chio.BanchoChannelRevoked, nil)
+}
+
 func (client *B282) WriteChannelAvailable(stream io.Writer, channel chio.Channel) error {
-	return nil
+	return client.unsupported(chio.BanchoChannelAvailable, nil)
 }
+
 func (client *B282) WriteChannelAvailableAutojoin(stream io.Writer, channel chio.Channel) error {
-	return nil
+	return client.unsupported(chio.BanchoChannelAvailableAutojoin, nil)
 }
+
 func (client *B282) WriteBeatmapInfoReply(stream io.Writer, reply chio.BeatmapInfoReply) error {
-	return nil
+	return client.unsupported(chio.BanchoBeatmapInfoReply, nil)
+}
+
+func (client *B282) WriteLoginPermissions(stream io.Writer, permissions uint32) error {
+	return client.unsupported(chio.BanchoLoginPermissions, nil)
+}
+
+func (client *B282) WriteFriendsList(stream io.Writer, userIds []int32) error {
+	return client.unsupported(chio.BanchoFriendsList, nil)
+}
+
+func (client *B282) WriteProtocolNegotiation(stream io.Writer, version int32) error {
+	return client.unsupported(chio.BanchoProtocolNegotiation, nil)
+}
+
+func (client *B282) WriteTitleUpdate(stream io.Writer, update chio.TitleUpdate) error {
+	return client.unsupported(chio.BanchoTitleUpdate, nil)
+}
+
+func (client *B282) WriteMonitor(stream io.Writer) error {
+	return client.unsupported(chio.BanchoMonitor, nil)
+}
+
+func (client *B282) WriteMatchPlayerSkipped(stream io.Writer, slotId int32) error {
+	return client.unsupported(chio.BanchoMatchPlayerSkipped, nil)
+}
+
+func (client *B282) WriteRestart(stream io.Writer, retryMs int32) error {
+	return client.unsupported(chio.BanchoRestart, func() error {
+		return client.Instance.WriteAnnouncement(stream, "Bancho is restarting, please wait...")
+	})
+}
+
+func (client *B282) WriteInvite(stream io.Writer, message chio.Message) error {
+	return client.unsupported(chio.BanchoInvite, func() error {
+		return client.Instance.WriteMessage(stream, chio.Message{
+			Sender:   message.Sender,
+			Content:  message.Content,
+			Target:   "#osu",
+			SenderId: message.SenderId,
+		})
+	})
+}
+
+func (client *B282) WriteChannelInfoComplete(stream io.Writer) error {
+	return client.unsupported(chio.BanchoChannelInfoComplete, nil)
+}
+
+func (client *B282) WriteMatchChangePassword(stream io.Writer, password string) error {
+	return client.unsupported(chio.BanchoMatchChangePassword, nil)
+}
+
+func (client *B282) WriteSilenceInfo(stream io.Writer, timeRemaining int32) error {
+	return client.unsupported(chio.BanchoSilenceInfo, func() error {
+		if timeRemaining <= 0 {
+			return nil
+		}
+		duration := time.Duration(timeRemaining) * time.Second
+		return client.writeBotMessage(stream, fmt.Sprintf("You are silenced for %s.", duration))
+	})
+}
+
+func (client *B282) WriteUserSilenced(stream io.Writer, userId uint32) error {
+	return client.unsupported(chio.BanchoUserSilenced, nil)
+}
+
+func (client *B282) WriteUserDMsBlocked(stream io.Writer, targetName string) error {
+	return client.unsupported(chio.BanchoUserDMsBlocked, nil)
+}
+
+func (client *B282) WriteTargetIsSilenced(stream io.Writer, targetName string) error {
+	return client.unsupported(chio.BanchoTargetIsSilenced, nil)
+}
+
+func (client *B282) WriteVersionUpdateForced(stream io.Writer) error {
+	return client.unsupported(chio.BanchoVersionUpdateForced, nil)
+}
+
+func (client *B282) WriteSwitchServer(stream io.Writer, target int32) error {
+	return client.unsupported(chio.BanchoSwitchServer, nil)
+}
+
+func (client *B282) WriteAccountRestricted(stream io.Writer) error {
+	return client.unsupported(chio.BanchoAccountRestricted, nil)
+}
+
+func (client *B282) WriteRTX(stream io.Writer, message string) error {
+	return client.unsupported(chio.BanchoRTX, nil)
+}
+
+func (client *B282) WriteMatchAbort(stream io.Writer) error {
+	return client.unsupported(chio.BanchoMatchAbort, nil)
+}
+
+func (client *B282) WriteSwitchTournamentServer(stream io.Writer, ip string) error {
+	return client.unsupported(chio.BanchoSwitchTournamentServer, nil)
 }
-func (client *B282) WriteLoginPermissions(stream io.Writer, permissions uint32) error { return nil }
-func (client *B282) WriteFriendsList(stream io.Writer, userIds []int32) error         { return nil }
-func (client *B282) WriteProtocolNegotiation(stream io.Writer, version int32) error   { return nil }
-func (client *B282) WriteTitleUpdate(stream io.Writer, update chio.TitleUpdate) error { return nil }
-func (client *B282) WriteMonitor(stream io.Writer) error                              { return nil }
-func (client *B282) WriteMatchPlayerSkipped(stream io.Writer, slotId int32) error     { return nil }
-func (client *B282) WriteRestart(stream io.Writer, retryMs int32) error               { return nil }
-func (client *B282) WriteInvite(stream io.Writer, message chio.Message) error         { return nil }
-func (client *B282) WriteChannelInfoComplete(stream io.Writer) error                  { return nil }
-func (client *B282) WriteMatchChangePassword(stream io.Writer, password string) error { return nil }
-func (client *B282) WriteSilenceInfo(stream io.Writer, timeRemaining int32) error     { return nil }
-func (client *B282) WriteUserSilenced(stream io.Writer, userId uint32) error          { return nil }
-func (client *B282) WriteUserDMsBlocked(stream io.Writer, targetName string) error    { return nil }
-func (client *B282) WriteTargetIsSilenced(stream io.Writer, targetName string) error  { return nil }
-func (client *B282) WriteVersionUpdateForced(stream io.Writer) error                  { return nil }
-func (client *B282) WriteSwitchServer(stream io.Writer, target int32) error           { return nil }
-func (client *B282) WriteAccountRestricted(stream io.Writer) error                    { return nil }
-func (client *B282) WriteRTX(stream io.Writer, message string) error                  { return nil }
-func (client *B282) WriteMatchAbort(stream io.Writer) error                           { return nil }
-func (client *B282) WriteSwitchTournamentServer(stream io.Writer, ip string) error    { return nil }
@@ -1,7 +1,6 @@
 package clients
 
 import (
-	"bytes"
 	"io"
 
 	chio "github.com/Lekuruu/chio-go"
@@ -18,29 +17,29 @@ func (client *B291) WriteGetAttention(stream io.Writer) error {
 }
 
 func (client *B291) WriteAnnouncement(stream io.Writer, message string) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteString(writer, message)
 	return client.WritePacket(stream, chio.BanchoAnnounce, writer.Bytes())
 }
 
-func (client *B291) WriteRestart(stream io.Writer, retryMs int32) error {
-	// NOTE: This is a backport of the actual restart packet, that
-	// simply announces the server restart to the user.
-	return client.WriteAnnouncement(stream, "Bancho is restarting, please wait...")
-}
-
 func NewB291() *B291 {
 	base := NewB282()
-	base.SupportedPacketIds = append(base.SupportedPacketIds,
-		chio.BanchoGetAttention,
-		chio.BanchoAnnounce,
-	)
+	base.Packets = base.Packets.Extend(map[uint16]uint16{
+		chio.BanchoGetAttention: 24,
+		chio.BanchoAnnounce:     25,
+	})
 
 	client := &B291{B282: base}
 	base.Instance = client
 	return client
 }
 
+// New creates a new instance of b291, without the overrides of this one
+func (client *B291) New() chio.BanchoIO {
+	return NewB291()
+}
+
 func init() {
-	chio.RegisterClient(291, NewB291())
+	chio.RegisterClientRange(291, 293, NewB291())
 }
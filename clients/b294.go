@@ -1,7 +1,6 @@
 package clients
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 
@@ -15,7 +14,8 @@ type B294 struct {
 }
 
 func (client *B294) WriteMessage(stream io.Writer, message chio.Message) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteString(writer, message.Sender)
 	internal.WriteString(writer, message.Content)
 
@@ -46,8 +46,45 @@ func (client *B294) ReadPrivateMessage(reader io.Reader) (*chio.Message, error)
 	return &chio.Message{Sender: "", Content: content, Target: target, SenderId: 0}, nil
 }
 
+func (client *B294) WritePrivateMessage(writer io.Writer, message chio.Message) error {
+	internal.WriteString(writer, message.Target)
+	internal.WriteString(writer, message.Content)
+	return internal.WriteBoolean(writer, true)
+}
+
+func (client *B294) ReadBanchoMessage(reader io.Reader) (*chio.Message, error) {
+	sender, err := internal.ReadString(reader)
+	if err != nil {
+		return nil, err
+	}
+	content, err := internal.ReadString(reader)
+	if err != nil {
+		return nil, err
+	}
+	isDirectMessage, err := internal.ReadBoolean(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &chio.Message{Sender: sender, Content: content, Target: "#osu"}
+
+	if isDirectMessage {
+		// The target is the user that is receiving the message,
+		// which is not included in the packet
+		message.Target = ""
+	}
+
+	return message, nil
+}
+
 func (client *B294) WriteSpectateFrames(stream io.Writer, bundle chio.ReplayFrameBundle) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
+	client.WriteFrameBundle(writer, bundle)
+	return client.WritePacket(stream, chio.BanchoSpectateFrames, writer.Bytes())
+}
+
+func (client *B294) WriteFrameBundle(writer io.Writer, bundle chio.ReplayFrameBundle) error {
 	internal.WriteUint16(writer, uint16(len(bundle.Frames)))
 
 	for _, frame := range bundle.Frames {
@@ -67,7 +104,7 @@ func (client *B294) WriteSpectateFrames(stream io.Writer, bundle chio.ReplayFram
 		client.WriteScoreFrame(writer, bundle.Frame)
 	}
 
-	return client.WritePacket(stream, chio.BanchoSpectateFrames, writer.Bytes())
+	return nil
 }
 
 func (client *B294) ReadFrameBundle(reader io.Reader) (*chio.ReplayFrameBundle, error) {
@@ -188,7 +225,11 @@ func (client *B294) ReadScoreFrame(reader io.Reader) (*chio.ScoreFrame, error) {
 
 func NewB294() *B294 {
 	base := NewB291()
-	base.SupportedPacketIds = append(base.SupportedPacketIds, chio.OsuSendIrcMessagePrivate)
+	base.Packets = base.Packets.Extend(map[uint16]uint16{
+		chio.OsuSendIrcMessagePrivate: 26,
+	})
+
+	base.LayoutFeatures = base.LayoutFeatures.With(chio.FeatureSpectatorScoreFrames)
 
 	client := &B294{B291: base}
 	base.Instance = client
@@ -197,6 +238,11 @@ func NewB294() *B294 {
 	return client
 }
 
+// New creates a new instance of b294, without the overrides of this one
+func (client *B294) New() chio.BanchoIO {
+	return NewB294()
+}
+
 func init() {
-	chio.RegisterClient(294, NewB294())
+	chio.RegisterClientRange(294, 295, NewB294())
 }
@@ -1,7 +1,6 @@
 package clients
 
 import (
-	"bytes"
 	"io"
 
 	chio "github.com/Lekuruu/chio-go"
@@ -107,7 +106,13 @@ func (client *B296) ReadScoreFrame(reader io.Reader) (*chio.ScoreFrame, error) {
 }
 
 func (client *B296) WriteSpectateFrames(stream io.Writer, bundle chio.ReplayFrameBundle) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
+	client.WriteFrameBundle(writer, bundle)
+	return client.WritePacket(stream, chio.BanchoSpectateFrames, writer.Bytes())
+}
+
+func (client *B296) WriteFrameBundle(writer io.Writer, bundle chio.ReplayFrameBundle) error {
 	internal.WriteUint16(writer, uint16(len(bundle.Frames)))
 
 	for _, frame := range bundle.Frames {
@@ -127,7 +132,7 @@ func (client *B296) WriteSpectateFrames(stream io.Writer, bundle chio.ReplayFram
 		client.WriteScoreFrame(writer, bundle.Frame)
 	}
 
-	return client.WritePacket(stream, chio.BanchoSpectateFrames, writer.Bytes())
+	return nil
 }
 
 func (client *B296) ReadFrameBundle(reader io.Reader) (*chio.ReplayFrameBundle, error) {
@@ -160,6 +165,7 @@ func (client *B296) ReadFrameBundle(reader io.Reader) (*chio.ReplayFrameBundle,
 
 func NewB296() *B296 {
 	base := NewB294()
+	base.LayoutFeatures = base.LayoutFeatures.With(chio.FeatureScoreFrameTime)
 
 	client := &B296{B294: base}
 	base.Instance = client
@@ -167,6 +173,11 @@ func NewB296() *B296 {
 	return client
 }
 
+// New creates a new instance of b296, without the overrides of this one
+func (client *B296) New() chio.BanchoIO {
+	return NewB296()
+}
+
 func init() {
-	chio.RegisterClient(296, NewB296())
+	chio.RegisterClientRange(296, 297, NewB296())
 }
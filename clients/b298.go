@@ -30,19 +30,22 @@ func (client *B298) WriteMatchNew(stream io.Writer, match chio.Match) error {
 }
 
 func (client *B298) WriteMatchDisband(stream io.Writer, matchId int32) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteInt32(writer, matchId)
 	return client.WritePacket(stream, chio.BanchoMatchDisband, writer.Bytes())
 }
 
 func (client *B298) WriteLobbyJoin(stream io.Writer, userId int32) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteInt32(writer, userId)
 	return client.WritePacket(stream, chio.BanchoLobbyJoin, writer.Bytes())
 }
 
 func (client *B298) WriteLobbyPart(stream io.Writer, userId int32) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteInt32(writer, userId)
 	return client.WritePacket(stream, chio.BanchoLobbyPart, writer.Bytes())
 }
@@ -56,28 +59,44 @@ func (client *B298) WriteMatchJoinFail(stream io.Writer) error {
 }
 
 func (client *B298) WriteFellowSpectatorJoined(stream io.Writer, userId int32) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteInt32(writer, userId)
 	return client.WritePacket(stream, chio.BanchoFellowSpectatorJoined, writer.Bytes())
 }
 
 func (client *B298) WriteFellowSpectatorLeft(stream io.Writer, userId int32) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteInt32(writer, userId)
 	return client.WritePacket(stream, chio.BanchoFellowSpectatorLeft, writer.Bytes())
 }
 
+// matchSlots returns exactly slotSize slots of the match, where missing slots are locked
+func matchSlots(match chio.Match, slotSize int) []*chio.MatchSlot {
+	slots := make([]*chio.MatchSlot, slotSize)
+	for i := range slots {
+		if i < len(match.Slots) && match.Slots[i] != nil {
+			slots[i] = match.Slots[i]
+		} else {
+			slots[i] = &chio.MatchSlot{Status: chio.SlotStatusLocked}
+		}
+	}
+	return slots
+}
+
 func (client *B298) WriteMatch(match chio.Match) []byte {
 	slotSize := client.MatchSlotSize()
 
+	slots := matchSlots(match, slotSize)
 	slotsOpen := make([]bool, slotSize)
 	slotsUsed := make([]bool, slotSize)
 	slotsReady := make([]bool, slotSize)
 
 	for i := 0; i < slotSize; i++ {
-		slotsOpen[i] = match.Slots[i].Status == chio.SlotStatusOpen
-		slotsUsed[i] = match.Slots[i].HasPlayer()
-		slotsReady[i] = match.Slots[i].Status == chio.SlotStatusReady
+		slotsOpen[i] = slots[i].Status == chio.SlotStatusOpen
+		slotsUsed[i] = slots[i].HasPlayer()
+		slotsReady[i] = slots[i].Status == chio.SlotStatusReady
 	}
 
 	writer := bytes.NewBuffer([]byte{})
@@ -92,8 +111,8 @@ func (client *B298) WriteMatch(match chio.Match) []byte {
 	internal.WriteBoolList(writer, slotsReady, client.Instance.MatchSlotSize())
 
 	for i := 0; i < slotSize; i++ {
-		if match.Slots[i].HasPlayer() {
-			internal.WriteInt32(writer, match.Slots[i].UserId)
+		if slots[i].HasPlayer() {
+			internal.WriteInt32(writer, slots[i].UserId)
 		}
 	}
 
@@ -190,36 +209,48 @@ func (client *B298) ReadMatchJoin(reader io.Reader) (*chio.MatchJoin, error) {
 	return &chio.MatchJoin{MatchId: matchId}, nil
 }
 
-func (client *B298) ReadMatchChangeSlot(reader io.Reader) (int32, error) {
-	return internal.ReadInt32(reader)
+func (client *B298) WriteMatchJoin(writer io.Writer, join chio.MatchJoin) error {
+	return internal.WriteInt32(writer, join.MatchId)
 }
 
-func (client *B298) ReadMatchLock(reader io.Reader) (int32, error) {
-	return internal.ReadInt32(reader)
+func (client *B298) ReadMatchChangeSlot(reader io.Reader) (*chio.MatchChangeSlot, error) {
+	slotId, err := internal.ReadInt32(reader)
+	if err != nil {
+		return nil, err
+	}
+	return &chio.MatchChangeSlot{SlotId: slotId}, nil
+}
+
+func (client *B298) ReadMatchLock(reader io.Reader) (*chio.MatchLock, error) {
+	slotId, err := internal.ReadInt32(reader)
+	if err != nil {
+		return nil, err
+	}
+	return &chio.MatchLock{SlotId: slotId}, nil
 }
 
 func NewB298() *B298 {
 	base := NewB296()
-	base.SupportedPacketIds = append(base.SupportedPacketIds,
-		chio.BanchoMatchUpdate,
-		chio.BanchoMatchNew,
-		chio.BanchoMatchDisband,
-		chio.OsuLobbyPart,
-		chio.OsuLobbyJoin,
-		chio.OsuMatchCreate,
-		chio.OsuMatchJoin,
-		chio.OsuMatchPart,
-		chio.BanchoLobbyJoin,
-		chio.BanchoLobbyPart,
-		chio.BanchoMatchJoinSuccess,
-		chio.BanchoMatchJoinFail,
-		chio.OsuMatchChangeSlot,
-		chio.OsuMatchReady,
-		chio.OsuMatchLock,
-		chio.OsuMatchChangeSettings,
-		chio.BanchoFellowSpectatorJoined,
-		chio.BanchoFellowSpectatorLeft,
-	)
+	base.Packets = base.Packets.Extend(map[uint16]uint16{
+		chio.BanchoMatchUpdate:           27,
+		chio.BanchoMatchNew:              28,
+		chio.BanchoMatchDisband:          29,
+		chio.OsuLobbyPart:                30,
+		chio.OsuLobbyJoin:                31,
+		chio.OsuMatchCreate:              32,
+		chio.OsuMatchJoin:                33,
+		chio.OsuMatchPart:                34,
+		chio.BanchoLobbyJoin:             35,
+		chio.BanchoLobbyPart:             36,
+		chio.BanchoMatchJoinSuccess:      37,
+		chio.BanchoMatchJoinFail:         38,
+		chio.OsuMatchChangeSlot:          39,
+		chio.OsuMatchReady:               40,
+		chio.OsuMatchLock:                41,
+		chio.OsuMatchChangeSettings:      42,
+		chio.BanchoFellowSpectatorJoined: 43,
+		chio.BanchoFellowSpectatorLeft:   44,
+	})
 
 	client := &B298{B296: base}
 	base.Instance = client
@@ -235,6 +266,11 @@ func NewB298() *B298 {
 	return client
 }
 
+// New creates a new instance of b298, without the overrides of this one
+func (client *B298) New() chio.BanchoIO {
+	return NewB298()
+}
+
 func init() {
-	chio.RegisterClient(298, NewB298())
+	chio.RegisterClientRange(298, 311, NewB298())
 }
@@ -19,7 +19,8 @@ func (client *B312) WriteMatchStart(stream io.Writer, match chio.Match) error {
 }
 
 func (client *B312) WriteMatchScoreUpdate(stream io.Writer, frame chio.ScoreFrame) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	client.WriteScoreFrame(writer, &frame)
 	return client.WritePacket(stream, chio.BanchoMatchScoreUpdate, writer.Bytes())
 }
@@ -27,14 +28,15 @@ func (client *B312) WriteMatchScoreUpdate(stream io.Writer, frame chio.ScoreFram
 func (client *B312) WriteMatch(match chio.Match) []byte {
 	slotSize := client.MatchSlotSize()
 
+	slots := matchSlots(match, slotSize)
 	slotsOpen := make([]bool, slotSize)
 	slotsUsed := make([]bool, slotSize)
 	slotsReady := make([]bool, slotSize)
 
 	for i := 0; i < slotSize; i++ {
-		slotsOpen[i] = match.Slots[i].Status == chio.SlotStatusOpen
-		slotsUsed[i] = match.Slots[i].HasPlayer()
-		slotsReady[i] = match.Slots[i].Status == chio.SlotStatusReady
+		slotsOpen[i] = slots[i].Status == chio.SlotStatusOpen
+		slotsUsed[i] = slots[i].HasPlayer()
+		slotsReady[i] = slots[i].Status == chio.SlotStatusReady
 	}
 
 	writer := bytes.NewBuffer([]byte{})
@@ -50,8 +52,8 @@ func (client *B312) WriteMatch(match chio.Match) []byte {
 	internal.WriteBoolList(writer, slotsReady, client.Instance.MatchSlotSize())
 
 	for i := 0; i < slotSize; i++ {
-		if match.Slots[i].HasPlayer() {
-			internal.WriteInt32(writer, match.Slots[i].UserId)
+		if slots[i].HasPlayer() {
+			internal.WriteInt32(writer, slots[i].UserId)
 		}
 	}
 
@@ -151,13 +153,14 @@ func (client *B312) ReadMatchScoreUpdate(reader io.Reader) (*chio.ScoreFrame, er
 
 func NewB312() *B312 {
 	base := NewB298()
-	base.SupportedPacketIds = append(base.SupportedPacketIds,
-		chio.OsuMatchStart,
-		chio.BanchoMatchStart,
-		chio.OsuMatchScoreUpdate,
-		chio.BanchoMatchScoreUpdate,
-		chio.OsuMatchComplete,
-	)
+	// "OsuMatchStart" is the last packet that is shifted by one
+	base.Packets = base.Packets.Extend(map[uint16]uint16{
+		chio.OsuMatchStart:          45,
+		chio.BanchoMatchStart:       46,
+		chio.OsuMatchScoreUpdate:    47,
+		chio.BanchoMatchScoreUpdate: 48,
+		chio.OsuMatchComplete:       49,
+	})
 
 	client := &B312{B298: base}
 	base.Instance = client
@@ -167,6 +170,11 @@ func NewB312() *B312 {
 	return client
 }
 
+// New creates a new instance of b312, without the overrides of this one
+func (client *B312) New() chio.BanchoIO {
+	return NewB312()
+}
+
 func init() {
-	chio.RegisterClient(312, NewB312())
+	chio.RegisterClientRange(312, 319, NewB312())
 }
@@ -1,7 +1,6 @@
 package clients
 
 import (
-	"bytes"
 	"io"
 
 	chio "github.com/Lekuruu/chio-go"
@@ -14,13 +13,24 @@ type B320 struct {
 }
 
 func (client *B320) WriteMessage(stream io.Writer, message chio.Message) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 	internal.WriteString(writer, message.Sender)
 	internal.WriteString(writer, message.Content)
 	internal.WriteString(writer, message.Target)
 	return client.WritePacket(stream, chio.BanchoSendMessage, writer.Bytes())
 }
 
+func (client *B320) WriteIrcMessage(writer io.Writer, message chio.Message) error {
+	internal.WriteString(writer, message.Sender)
+	internal.WriteString(writer, message.Content)
+	return internal.WriteString(writer, message.Target)
+}
+
+func (client *B320) WritePrivateMessage(writer io.Writer, message chio.Message) error {
+	return client.WriteIrcMessage(writer, message)
+}
+
 func (client *B320) ReadMessage(reader io.Reader) (*chio.Message, error) {
 	sender, err := internal.ReadString(reader)
 	if err != nil {
@@ -46,14 +56,24 @@ func (client *B320) ReadPrivateMessage(reader io.Reader) (*chio.Message, error)
 	return client.ReadMessage(reader)
 }
 
+func (client *B320) ReadBanchoMessage(reader io.Reader) (*chio.Message, error) {
+	return client.ReadMessage(reader)
+}
+
 func NewB320() *B320 {
 	base := NewB312()
+	base.LayoutFeatures = base.LayoutFeatures.With(chio.FeatureMessageTargets)
 
 	client := &B320{B312: base}
 	base.Instance = client
 	return client
 }
 
+// New creates a new instance of b320, without the overrides of this one
+func (client *B320) New() chio.BanchoIO {
+	return NewB320()
+}
+
 func init() {
-	chio.RegisterClient(320, NewB320())
+	chio.RegisterClientRange(320, 322, NewB320())
 }
@@ -1,7 +1,6 @@
 package clients
 
 import (
-	"bytes"
 	"io"
 
 	chio "github.com/Lekuruu/chio-go"
@@ -13,44 +12,9 @@ type B323 struct {
 	*B320
 }
 
-func (client *B323) ConvertInputPacketId(packetId uint16) uint16 {
-	if packetId == 11 {
-		// "IrcJoin" packet
-		return chio.BanchoHandleIrcJoin
-	}
-
-	if packetId == 50 {
-		// "MatchChangeBeatmap" packet
-		return chio.OsuMatchChangeBeatmap
-	}
-
-	if packetId > 11 && packetId <= 45 {
-		packetId -= 1
-	}
-
-	return packetId
-}
-
-func (client *B323) ConvertOutputPacketId(packetId uint16) uint16 {
-	if packetId == chio.BanchoHandleIrcJoin {
-		// "IrcJoin" packet
-		return 11
-	}
-
-	if packetId == chio.OsuMatchChangeBeatmap {
-		// "MatchChangeBeatmap" packet
-		return 50
-	}
-
-	if packetId >= 11 && packetId < 45 {
-		return packetId + 1
-	}
-
-	return packetId
-}
-
 func (client *B323) WriteUserStats(stream io.Writer, info chio.UserInfo) error {
-	writer := bytes.NewBuffer([]byte{})
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
 
 	if info.Presence.IsIrc {
 		internal.WriteString(writer, info.Name)
@@ -80,32 +44,101 @@ func (client *B323) WriteUserStats(stream io.Writer, info chio.UserInfo) error {
 
 func (client *B323) WriteUserPresence(stream io.Writer, info chio.UserInfo) error {
 	if info.Presence.IsIrc {
-		writer := bytes.NewBuffer([]byte{})
+		writer := internal.GetBuffer()
+		defer internal.PutBuffer(writer)
 		internal.WriteString(writer, info.Name)
 		return client.WritePacket(stream, chio.BanchoHandleIrcJoin, writer.Bytes())
 	}
 
 	// We assume that the client has not seen this user before, so
 	// we send two packets: one for the user stats, and one for the "presence".
-	info.Status.UpdateStats = true
+	// The status is copied, to avoid changing the status of the caller.
+	status := *info.Status
+	info.Status = &status
+
+	status.UpdateStats = true
 	err := client.WriteUserStats(stream, info)
 	if err != nil {
 		return err
 	}
 
-	info.Status.UpdateStats = false
+	status.UpdateStats = false
 	return client.WriteUserStats(stream, info)
 }
 
+func (client *B323) ReadUserStats(reader io.Reader) (*chio.UserInfo, error) {
+	userId, err := internal.ReadUint32(reader)
+	if err != nil {
+		return nil, err
+	}
+	readStats, err := internal.ReadBoolean(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &chio.UserInfo{Id: int32(userId)}
+
+	if readStats {
+		info.Stats = &chio.UserStats{}
+		info.Name, err = internal.ReadString(reader)
+		if err != nil {
+			return nil, err
+		}
+		info.Stats.Rscore, err = internal.ReadUint64(reader)
+		if err != nil {
+			return nil, err
+		}
+		accuracy, err := internal.ReadFloat32(reader)
+		if err != nil {
+			return nil, err
+		}
+		info.Stats.Accuracy = float64(accuracy)
+		playcount, err := internal.ReadUint32(reader)
+		if err != nil {
+			return nil, err
+		}
+		info.Stats.Playcount = int32(playcount)
+		info.Stats.Tscore, err = internal.ReadUint64(reader)
+		if err != nil {
+			return nil, err
+		}
+		info.Stats.Rank, err = internal.ReadInt32(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		// Avatar filename, which is derived from the user id
+		_, err = internal.ReadString(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		info.Presence, err = client.ReadPresence(reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	info.Status, err = client.ReadStatus(reader)
+	if err != nil {
+		return nil, err
+	}
+	info.Status.UpdateStats = readStats
+
+	return info, nil
+}
+
 func (client *B323) ReadMatchChangeBeatmap(reader io.Reader) (*chio.Match, error) {
 	return client.ReadMatch(reader)
 }
 
 func NewB323() *B323 {
 	base := NewB320()
-	base.SupportedPacketIds = append(base.SupportedPacketIds,
-		chio.OsuMatchChangeBeatmap,
-	)
+	base.Packets = base.Packets.Extend(map[uint16]uint16{
+		chio.OsuMatchChangeBeatmap: 50,
+	})
+
+	base.LayoutFeatures = base.LayoutFeatures.With(chio.FeatureStatusUpdates)
 
 	client := &B323{B320: base}
 	base.Instance = client
@@ -113,6 +146,13 @@ func NewB323() *B323 {
 	return client
 }
 
+// New creates a new instance of b323, without the overrides of this one
+func (client *B323) New() chio.BanchoIO {
+	return NewB323()
+}
+
 func init() {
-	chio.RegisterClient(323, NewB323())
+	// No later version has been verified against b323 yet, which
+	// is left to the resolve policy instead of assuming a range
+	chio.RegisterClientRange(323, 323, NewB323())
 }
@@ -0,0 +1,148 @@
+package clients
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+// The results of these benchmarks are tracked in testdata/bench/baseline.txt.
+// After changing a hot path, compare against it with benchstat:
+//
+//	go test -run '^$' -bench . -benchmem -count 6 > new.txt
+//	benchstat testdata/bench/baseline.txt new.txt
+
+// benchFrameCount is the amount of replay frames in a bundle.
+// Clients send their frames roughly every second, at 60 frames per second.
+const benchFrameCount = 60
+
+func benchFrameBundle() chio.ReplayFrameBundle {
+	bundle := goldenFrameBundle()
+	bundle.Frames = make([]*chio.ReplayFrame, benchFrameCount)
+
+	for i := range bundle.Frames {
+		bundle.Frames[i] = &chio.ReplayFrame{
+			ButtonState: uint8(i % 4),
+			MouseX:      float32(256 + i),
+			MouseY:      float32(192 - i),
+			Time:        int32(31000 + i*16),
+		}
+	}
+	return bundle
+}
+
+// benchVersions runs a benchmark for every registered client version that supports the features
+func benchVersions(b *testing.B, features []chio.Feature, bench func(b *testing.B, server chio.BanchoIO)) {
+	for _, version := range chio.Versions() {
+		server := chio.GetClientInterface(version)
+		if !supportsAll(server, features) {
+			continue
+		}
+		b.Run(fmt.Sprintf("b%d", version), func(b *testing.B) {
+			b.ReportAllocs()
+			bench(b, server)
+		})
+	}
+}
+
+func supportsAll(server chio.BanchoIO, features []chio.Feature) bool {
+	for _, feature := range features {
+		if !chio.Supports(server, feature) {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkWriteUserStats(b *testing.B) {
+	info := goldenUserInfo()
+
+	benchVersions(b, nil, func(b *testing.B, server chio.BanchoIO) {
+		for i := 0; i < b.N; i++ {
+			server.WriteUserStats(io.Discard, info)
+		}
+	})
+}
+
+func BenchmarkWriteUserPresence(b *testing.B) {
+	info := goldenUserInfo()
+
+	benchVersions(b, nil, func(b *testing.B, server chio.BanchoIO) {
+		for i := 0; i < b.N; i++ {
+			server.WriteUserPresence(io.Discard, info)
+		}
+	})
+}
+
+func BenchmarkWriteMatch(b *testing.B) {
+	match := goldenMatch()
+
+	benchVersions(b, []chio.Feature{chio.FeatureMultiplayer}, func(b *testing.B, server chio.BanchoIO) {
+		for i := 0; i < b.N; i++ {
+			server.WriteMatchUpdate(io.Discard, match)
+		}
+	})
+}
+
+func BenchmarkWriteSpectateFrames(b *testing.B) {
+	bundle := benchFrameBundle()
+
+	benchVersions(b, []chio.Feature{chio.FeatureSpectating}, func(b *testing.B, server chio.BanchoIO) {
+		for i := 0; i < b.N; i++ {
+			server.WriteSpectateFrames(io.Discard, bundle)
+		}
+	})
+}
+
+func BenchmarkWriteMessage(b *testing.B) {
+	message := goldenMessage()
+
+	benchVersions(b, nil, func(b *testing.B, server chio.BanchoIO) {
+		for i := 0; i < b.N; i++ {
+			server.WriteMessage(io.Discard, message)
+		}
+	})
+}
+
+func BenchmarkReadPacket(b *testing.B) {
+	benchVersions(b, []chio.Feature{chio.FeatureSpectating}, func(b *testing.B, server chio.BanchoIO) {
+		stream := &bytes.Buffer{}
+		if err := NewOsuClient(server).WriteSpectateFrames(stream, benchFrameBundle()); err != nil {
+			b.Fatal(err)
+		}
+
+		data := stream.Bytes()
+		reader := bytes.NewReader(data)
+		b.SetBytes(int64(len(data)))
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			reader.Reset(data)
+			if _, err := server.ReadPacket(reader); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkCompression writes messages of increasing size,
+// so that the cost of compressing the payload dominates
+func BenchmarkCompression(b *testing.B) {
+	for _, size := range []int{64, 1024, 16384} {
+		message := goldenMessage()
+		message.Content = strings.Repeat("osu! ", size/5)
+
+		b.Run(fmt.Sprintf("%dB", size), func(b *testing.B) {
+			benchVersions(b, nil, func(b *testing.B, server chio.BanchoIO) {
+				b.SetBytes(int64(len(message.Content)))
+				for i := 0; i < b.N; i++ {
+					server.WriteMessage(io.Discard, message)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package clients
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+func TestCompressionPolicies(t *testing.T) {
+	policies := map[string]*chio.CompressionPolicy{
+		"always":    chio.NewCompressionPolicy(chio.CompressAlways),
+		"threshold": chio.NewCompressionPolicy(chio.CompressThreshold),
+		"none":      chio.NewCompressionPolicy(chio.CompressNone),
+		"level":     chio.NewCompressionPolicy(chio.CompressAlways).WithLevel(gzip.BestSpeed),
+	}
+
+	for name, policy := range policies {
+		t.Run(name, func(t *testing.T) {
+			server := NewB298()
+			server.OverrideCompression(policy)
+			osu := NewOsuClient(server)
+
+			stream := &bytes.Buffer{}
+			server.WriteLoginReply(stream, 1000)
+			server.WriteMatchUpdate(stream, goldenMatch())
+
+			// The login reply is a single int32, which is never compressed by the threshold
+			length := binary.LittleEndian.Uint32(stream.Bytes()[2:6])
+			if compressed := length != 4; compressed != (policy.Default() == chio.CompressAlways) {
+				t.Fatalf("expected login reply of %d bytes to follow %s", length, policy.Default())
+			}
+
+			packet, err := osu.ReadPacket(stream)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reply := packet.Data.(*chio.LoginReply); reply.Reply != 1000 {
+				t.Fatalf("expected login reply 1000, got %d", reply.Reply)
+			}
+
+			packet, err = osu.ReadPacket(stream)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGoldenMatch(t, packet)
+		})
+	}
+}
+
+func TestCompressionOverride(t *testing.T) {
+	server := NewB298()
+	server.OverrideCompression(chio.NewCompressionPolicy(chio.CompressAlways).Set(chio.BanchoLoginReply, chio.CompressNone))
+
+	stream := &bytes.Buffer{}
+	server.WriteLoginReply(stream, 1000)
+
+	if length := binary.LittleEndian.Uint32(stream.Bytes()[2:6]); length != 4 {
+		t.Fatalf("expected an uncompressed login reply, got %d bytes", length)
+	}
+	if _, err := NewOsuClient(server).ReadPacket(stream); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompressionInvalidLevel(t *testing.T) {
+	server := NewB298()
+	server.OverrideCompression(chio.NewCompressionPolicy(chio.CompressAlways).WithLevel(42))
+
+	if err := server.WriteLoginReply(&bytes.Buffer{}, 1); err == nil {
+		t.Fatal("expected an error for an invalid compression level")
+	}
+}
+
+func TestDecompressionError(t *testing.T) {
+	server := NewB298()
+
+	stream := &bytes.Buffer{}
+	server.WriteMessage(stream, goldenMessage())
+
+	// Cut off the gzip trailer, and fix up the length of the frame
+	frame := stream.Bytes()
+	frame = frame[:len(frame)-8]
+	binary.LittleEndian.PutUint32(frame[2:6], uint32(len(frame)-6))
+
+	if _, err := NewOsuClient(server).ReadPacket(bytes.NewReader(frame)); err == nil {
+		t.Fatal("expected an error for a truncated payload")
+	}
+}
@@ -0,0 +1,97 @@
+package clients
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+func TestFallbackModes(t *testing.T) {
+	writers := map[string]struct {
+		packetId uint16
+		write    func(client *B282, stream io.Writer) error
+		emulated string // Content of the message that replaces the packet
+	}{
+		"restart": {
+			chio.BanchoRestart,
+			func(client *B282, stream io.Writer) error { return client.WriteRestart(stream, 5000) },
+			"Bancho is restarting, please wait...",
+		},
+		"private message": {
+			chio.BanchoSendMessage,
+			func(client *B282, stream io.Writer) error {
+				return client.WriteMessage(stream, chio.Message{Sender: "peppy", Content: "hi", Target: "Lekuruu"})
+			},
+			"(PM to Lekuruu) hi",
+		},
+		"announcement": {
+			chio.BanchoAnnounce,
+			func(client *B282, stream io.Writer) error { return client.WriteAnnouncement(stream, "maintenance") },
+			"maintenance",
+		},
+	}
+
+	for name, writer := range writers {
+		t.Run(name+"/drop", func(t *testing.T) {
+			client := NewB282()
+			client.OverrideFallback(chio.NewFallbackPolicy(chio.FallbackDrop))
+
+			stream := &bytes.Buffer{}
+			if err := writer.write(client, stream); err != nil {
+				t.Fatal(err)
+			}
+			if stream.Len() != 0 {
+				t.Fatalf("expected the packet to be dropped, got %d bytes", stream.Len())
+			}
+		})
+
+		t.Run(name+"/emulate", func(t *testing.T) {
+			client := NewB282()
+			client.OverrideFallback(chio.NewFallbackPolicy(chio.FallbackEmulate).WithBot("Chio"))
+
+			stream := &bytes.Buffer{}
+			if err := writer.write(client, stream); err != nil {
+				t.Fatal(err)
+			}
+
+			message := decodeGolden[chio.Message](t, readOsuPacket(t, client, stream))
+			if message.Content != writer.emulated {
+				t.Fatalf("expected the message %q, got %q", writer.emulated, message.Content)
+			}
+			if writer.packetId != chio.BanchoSendMessage && message.Sender != "Chio" {
+				t.Fatalf("expected the message to be sent by the bot, got %q", message.Sender)
+			}
+		})
+
+		t.Run(name+"/error", func(t *testing.T) {
+			client := NewB282()
+			client.OverrideFallback(chio.NewFallbackPolicy(chio.FallbackDrop).Set(writer.packetId, chio.FallbackError))
+
+			stream := &bytes.Buffer{}
+			if err := writer.write(client, stream); !errors.Is(err, chio.ErrNotImplemented) {
+				t.Fatalf("expected ErrNotImplemented, got %v", err)
+			}
+			if stream.Len() != 0 {
+				t.Fatalf("expected nothing to be written, got %d bytes", stream.Len())
+			}
+		})
+	}
+}
+
+func TestFallbackPolicy(t *testing.T) {
+	policy := chio.NewFallbackPolicy(chio.FallbackDrop).Set(chio.BanchoRestart, chio.FallbackEmulate)
+	policy.WithDefault(chio.FallbackError)
+
+	if policy.Default() != chio.FallbackError || policy.Mode(chio.BanchoAnnounce) != chio.FallbackError {
+		t.Fatalf("expected the default mode to be changed, got %s", policy.Default())
+	}
+	if policy.Mode(chio.BanchoRestart) != chio.FallbackEmulate {
+		t.Fatalf("expected the override to be kept, got %s", policy.Mode(chio.BanchoRestart))
+	}
+	if policy.BotName() != "BanchoBot" {
+		t.Fatalf("unexpected bot name %q", policy.BotName())
+	}
+}
@@ -0,0 +1,126 @@
+package clients
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+func TestConvertButtonState(t *testing.T) {
+	tests := map[uint8]uint8{
+		chio.ButtonStateNoButton:                       chio.ButtonStateNoButton,
+		chio.ButtonStateLeft1 | chio.ButtonStateLeft2:  chio.ButtonStateLeft1,
+		chio.ButtonStateRight2:                         chio.ButtonStateRight1,
+		chio.ButtonStateLeft2 | chio.ButtonStateSmoke:  chio.ButtonStateLeft1,
+		chio.ButtonStateSmoke:                          chio.ButtonStateNoButton,
+		chio.ButtonStateRight1 | chio.ButtonStateLeft2: chio.ButtonStateLeft1 | chio.ButtonStateRight1,
+	}
+	for state, expected := range tests {
+		if converted := chio.ConvertButtonState(state); converted != expected {
+			t.Errorf("expected %08b to be converted to %08b, got %08b", state, expected, converted)
+		}
+	}
+}
+
+func TestRelayFrames(t *testing.T) {
+	bundle := goldenFrameBundle()
+	bundle.Frames[0].ButtonState = chio.ButtonStateRight2 | chio.ButtonStateSmoke
+
+	for _, sourceVersion := range chio.Versions() {
+		source := chio.GetClientInterface(sourceVersion)
+		if !chio.Supports(source, chio.FeatureSpectating) {
+			continue
+		}
+
+		// Frames of the host, as they were read by the server
+		stream := &bytes.Buffer{}
+		NewOsuClient(source).WriteSpectateFrames(stream, bundle)
+		packet, err := source.ReadPacket(stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+		received := *packet.Data.(*chio.ReplayFrameBundle)
+
+		for _, targetVersion := range chio.Versions() {
+			target := chio.GetClientInterface(targetVersion)
+			if !chio.Supports(target, chio.FeatureSpectating) {
+				continue
+			}
+
+			t.Run(fmt.Sprintf("b%d/b%d", sourceVersion, targetVersion), func(t *testing.T) {
+				stream := &bytes.Buffer{}
+				if err := chio.RelayFrames(stream, received, source, target); err != nil {
+					t.Fatal(err)
+				}
+				relayed := decodeGolden[chio.ReplayFrameBundle](t, readOsuPacket(t, target, stream))
+
+				if len(relayed.Frames) != 2 || relayed.Frames[0].ButtonState != chio.ButtonStateRight1 {
+					t.Fatalf("unexpected frames %+v", relayed.Frames)
+				}
+
+				scoreFrames := chio.Supports(source, chio.FeatureSpectatorScoreFrames) && chio.Supports(target, chio.FeatureSpectatorScoreFrames)
+				if (relayed.Frame != nil) != scoreFrames {
+					t.Fatalf("expected score frame: %v, got %+v", scoreFrames, relayed.Frame)
+				}
+				if !scoreFrames || !chio.Supports(target, chio.FeatureScoreFrameTime) {
+					return
+				}
+
+				expectedTime := bundle.Frame.Time
+				if !chio.Supports(source, chio.FeatureScoreFrameTime) {
+					expectedTime = bundle.Frames[1].Time
+				}
+				if relayed.Frame.Time != expectedTime || relayed.Frame.TotalScore != bundle.Frame.TotalScore {
+					t.Fatalf("unexpected score frame %+v", relayed.Frame)
+				}
+			})
+		}
+	}
+}
+
+func TestConvertWatchingOther(t *testing.T) {
+	bundle := chio.ReplayFrameBundle{Action: chio.ReplayActionWatchingOther, Extra: 5}
+	converted := chio.ConvertFrameBundle(bundle, NewB323(), NewB323())
+
+	if converted.Action != chio.ReplayActionStandard || converted.Extra != 0 {
+		t.Fatalf("unexpected bundle %+v", converted)
+	}
+	if bundle.Action != chio.ReplayActionWatchingOther {
+		t.Fatal("the original bundle was modified")
+	}
+}
+
+func TestConvertSameVersion(t *testing.T) {
+	for _, version := range chio.Versions() {
+		client := chio.GetClientInterface(version)
+		if !chio.Supports(client, chio.FeatureSpectating) {
+			continue
+		}
+
+		stream := &bytes.Buffer{}
+		NewOsuClient(client).WriteSpectateFrames(stream, goldenFrameBundle())
+		packet, err := client.ReadPacket(stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		received := *packet.Data.(*chio.ReplayFrameBundle)
+		converted := chio.ConvertFrameBundle(received, client, client)
+
+		if !reflect.DeepEqual(converted, received) {
+			t.Errorf("b%d: expected the bundle to pass through unchanged, got %+v instead of %+v", version, converted, received)
+		}
+	}
+}
+
+func readOsuPacket(t *testing.T, io chio.BanchoIO, stream *bytes.Buffer) *chio.BanchoPacket {
+	t.Helper()
+	packet, err := NewOsuClient(io).ReadPacket(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return packet
+}
@@ -0,0 +1,119 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/internal"
+)
+
+// addGoldenSeeds adds the frames & payloads of the golden cases to the fuzzing corpus
+func addGoldenSeeds(f *testing.F, payloads bool) {
+	for i, version := range chio.Versions() {
+		server := chio.GetClientInterface(version)
+		osu := NewOsuClient(server)
+
+		for _, test := range goldenCases {
+			stream := &bytes.Buffer{}
+			if err := test.write(server, osu, stream); err != nil || stream.Len() == 0 {
+				continue
+			}
+			if !payloads {
+				f.Add(uint8(i), uint8(0), stream.Bytes())
+				continue
+			}
+			if frames := framePayloads(stream.Bytes()); len(frames) > 0 {
+				f.Add(uint8(i), uint8(0), frames[0])
+			}
+		}
+	}
+}
+
+// framePayloads returns the decompressed payload of every frame in data
+func framePayloads(data []byte) [][]byte {
+	payloads := make([][]byte, 0)
+	for len(data) >= 6 {
+		length := int(binary.LittleEndian.Uint32(data[2:6]))
+		if len(data) < 6+length {
+			break
+		}
+		payload, err := internal.DecompressData(data[6 : 6+length])
+		if err != nil {
+			break
+		}
+		payloads = append(payloads, payload)
+		data = data[6+length:]
+	}
+	return payloads
+}
+
+// fuzzVersion picks a registered client version for the fuzzer
+func fuzzVersion(index uint8) chio.BanchoIO {
+	versions := chio.Versions()
+	return chio.GetClientInterface(versions[int(index)%len(versions)])
+}
+
+// sortedReaders returns the readers of a registry in a stable order
+func sortedReaders(registry chio.ReaderRegistry) []chio.PacketReader {
+	ids := make([]uint16, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	readers := make([]chio.PacketReader, len(ids))
+	for i, id := range ids {
+		readers[i] = registry[id]
+	}
+	return readers
+}
+
+// FuzzReadPacket feeds raw frames into ReadPacket of both sides of a client version
+func FuzzReadPacket(f *testing.F) {
+	addGoldenSeeds(f, false)
+
+	f.Fuzz(func(t *testing.T, version uint8, side uint8, data []byte) {
+		server := fuzzVersion(version)
+
+		var read func(io.Reader) (*chio.BanchoPacket, error)
+		if side%2 == 0 {
+			read = server.ReadPacket
+		} else {
+			read = NewOsuClient(server).ReadPacket
+		}
+
+		reader := bytes.NewReader(data)
+		for reader.Len() > 0 {
+			remaining := reader.Len()
+			_, err := read(reader)
+
+			// Unsupported packets are skipped, and keep the stream intact
+			if err != nil && !errors.Is(err, chio.ErrNotImplemented) {
+				return
+			}
+			if reader.Len() == remaining {
+				t.Fatalf("ReadPacket did not consume any data")
+			}
+		}
+	})
+}
+
+// FuzzReaders feeds decompressed payloads into every reader of a client version
+func FuzzReaders(f *testing.F) {
+	addGoldenSeeds(f, true)
+
+	f.Fuzz(func(t *testing.T, version uint8, reader uint8, data []byte) {
+		server := fuzzVersion(version)
+
+		readers := sortedReaders(server.GetReaders())
+		readers = append(readers, sortedReaders(NewOsuClient(server).GetReaders())...)
+
+		read := readers[int(reader)%len(readers)]
+		read(server, bytes.NewReader(data))
+	})
+}
@@ -0,0 +1,316 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/internal"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenCase encodes a value with either side of a client version, and decodes it with the other.
+// Fixtures contain one line per frame, with the wire id and the decompressed payload as hex, so
+// that they pin the packet layout without depending on the output of the gzip implementation.
+type goldenCase struct {
+	name  string
+	write func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error
+	check func(t *testing.T, packet *chio.BanchoPacket)
+
+	// Whether the packet is written by the server, and read by the client
+	fromServer bool
+}
+
+func goldenMessage() chio.Message {
+	return chio.Message{Sender: "peppy", Content: "Hello, World!", Target: "#osu", SenderId: 2}
+}
+
+func goldenUserInfo() chio.UserInfo {
+	return chio.UserInfo{
+		Id:   2,
+		Name: "peppy",
+		Presence: &chio.UserPresence{
+			Timezone:     1,
+			CountryIndex: 14,
+			City:         "Perth",
+		},
+		Status: &chio.UserStatus{
+			Action:          chio.StatusPlaying,
+			Text:            "Kenji Ninuma - DISCO PRINCE",
+			BeatmapChecksum: "a5b99395a42bd55bc5eb1d2411cbdf8b",
+			BeatmapId:       75,
+			UpdateStats:     true,
+		},
+		Stats: &chio.UserStats{
+			Rank:      1,
+			Rscore:    1234567,
+			Tscore:    7654321,
+			Accuracy:  0.9876,
+			Playcount: 42,
+		},
+	}
+}
+
+func goldenScoreFrame() chio.ScoreFrame {
+	return chio.ScoreFrame{
+		Time:         31337,
+		Id:           1,
+		Total300:     300,
+		Total100:     20,
+		Total50:      3,
+		TotalGeki:    40,
+		TotalKatu:    5,
+		TotalMiss:    1,
+		TotalScore:   1000000,
+		MaxCombo:     500,
+		CurrentCombo: 250,
+		Perfect:      false,
+		Hp:           200,
+	}
+}
+
+func goldenFrameBundle() chio.ReplayFrameBundle {
+	frame := goldenScoreFrame()
+	return chio.ReplayFrameBundle{
+		Action: chio.ReplayActionStandard,
+		Frames: []*chio.ReplayFrame{
+			{ButtonState: 1, MouseX: 256, MouseY: 192, Time: 31000},
+			{ButtonState: 0, MouseX: 260.5, MouseY: 190.25, Time: 31016},
+		},
+		Frame: &frame,
+	}
+}
+
+func goldenMatch() chio.Match {
+	match := chio.Match{
+		Id:              3,
+		Type:            0,
+		Name:            "peppy's game",
+		BeatmapText:     "Kenji Ninuma - DISCO PRINCE [Normal]",
+		BeatmapId:       75,
+		BeatmapChecksum: "a5b99395a42bd55bc5eb1d2411cbdf8b",
+		HostId:          2,
+	}
+	for i := 0; i < 8; i++ {
+		match.Slots = append(match.Slots, &chio.MatchSlot{Status: chio.SlotStatusOpen})
+	}
+	match.Slots[0] = &chio.MatchSlot{UserId: 2, Status: chio.SlotStatusReady}
+	match.Slots[1] = &chio.MatchSlot{UserId: 3, Status: chio.SlotStatusNotReady}
+	match.Slots[7] = &chio.MatchSlot{Status: chio.SlotStatusLocked}
+	return match
+}
+
+var goldenCases = []goldenCase{
+	{
+		name:       "message",
+		fromServer: true,
+		write: func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error {
+			return server.WriteMessage(stream, goldenMessage())
+		},
+		check: func(t *testing.T, packet *chio.BanchoPacket) {
+			message := decodeGolden[chio.Message](t, packet)
+			if message.Sender != "peppy" || message.Content != "Hello, World!" {
+				t.Errorf("decoded %+v", message)
+			}
+		},
+	},
+	{
+		name: "send_message",
+		write: func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteSendIrcMessage(stream, goldenMessage())
+		},
+		check: func(t *testing.T, packet *chio.BanchoPacket) {
+			message := decodeGolden[chio.Message](t, packet)
+			if message.Content != "Hello, World!" {
+				t.Errorf("decoded %+v", message)
+			}
+		},
+	},
+	{
+		name:       "user_stats",
+		fromServer: true,
+		write: func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error {
+			return server.WriteUserStats(stream, goldenUserInfo())
+		},
+		check: func(t *testing.T, packet *chio.BanchoPacket) {
+			info := decodeGolden[chio.UserInfo](t, packet)
+			if info.Id != 2 {
+				t.Errorf("decoded %+v", info)
+			}
+		},
+	},
+	{
+		name:       "spectate_frames",
+		fromServer: true,
+		write: func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error {
+			return server.WriteSpectateFrames(stream, goldenFrameBundle())
+		},
+		check: checkGoldenFrameBundle,
+	},
+	{
+		name: "send_spectate_frames",
+		write: func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteSpectateFrames(stream, goldenFrameBundle())
+		},
+		check: checkGoldenFrameBundle,
+	},
+	{
+		name:       "match_update",
+		fromServer: true,
+		write: func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error {
+			return server.WriteMatchUpdate(stream, goldenMatch())
+		},
+		check: checkGoldenMatch,
+	},
+	{
+		name: "match_create",
+		write: func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteMatchCreate(stream, goldenMatch())
+		},
+		check: checkGoldenMatch,
+	},
+	{
+		name:       "match_score_update",
+		fromServer: true,
+		write: func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error {
+			return server.WriteMatchScoreUpdate(stream, goldenScoreFrame())
+		},
+		check: checkGoldenScoreFrame,
+	},
+	{
+		name: "send_match_score_update",
+		write: func(server chio.BanchoIO, osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteMatchScoreUpdate(stream, goldenScoreFrame())
+		},
+		check: checkGoldenScoreFrame,
+	},
+}
+
+func checkGoldenFrameBundle(t *testing.T, packet *chio.BanchoPacket) {
+	bundle := decodeGolden[chio.ReplayFrameBundle](t, packet)
+	if len(bundle.Frames) != 2 || bundle.Frames[1].MouseX != 260.5 {
+		t.Errorf("decoded %+v", bundle)
+	}
+}
+
+func checkGoldenMatch(t *testing.T, packet *chio.BanchoPacket) {
+	match := decodeGolden[chio.Match](t, packet)
+	if match.Name != "peppy's game" || match.BeatmapId != 75 {
+		t.Errorf("decoded %+v", match)
+	}
+}
+
+func checkGoldenScoreFrame(t *testing.T, packet *chio.BanchoPacket) {
+	frame := decodeGolden[chio.ScoreFrame](t, packet)
+	if frame.TotalScore != 1000000 || frame.MaxCombo != 500 {
+		t.Errorf("decoded %+v", frame)
+	}
+}
+
+func decodeGolden[T any](t *testing.T, packet *chio.BanchoPacket) T {
+	t.Helper()
+	data, err := chio.Decode[T](packet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestGolden(t *testing.T) {
+	for _, version := range chio.Versions() {
+		server := chio.GetClientInterface(version)
+		osu := NewOsuClient(server)
+
+		for _, test := range goldenCases {
+			t.Run(fmt.Sprintf("b%d/%s", version, test.name), func(t *testing.T) {
+				stream := &bytes.Buffer{}
+				if err := test.write(server, osu, stream); err != nil {
+					t.Fatal(err)
+				}
+
+				encoded := stream.Bytes()
+				path := filepath.Join("testdata", "golden", fmt.Sprintf("b%d", version), test.name+".golden")
+				compareGolden(t, path, formatFrames(t, encoded))
+
+				if len(encoded) == 0 {
+					// The packet is not supported by this version
+					return
+				}
+
+				reader := bytes.NewReader(encoded)
+				var packet *chio.BanchoPacket
+				var err error
+
+				if test.fromServer {
+					packet, err = osu.ReadPacket(reader)
+				} else {
+					packet, err = server.ReadPacket(reader)
+				}
+				if err != nil {
+					t.Fatalf("failed to decode: %v", err)
+				}
+				if reader.Len() > 0 {
+					t.Fatalf("%d trailing bytes after %s", reader.Len(), packet)
+				}
+				test.check(t, packet)
+			})
+		}
+	}
+}
+
+// formatFrames returns the wire id & decompressed payload of every frame
+func formatFrames(t *testing.T, data []byte) string {
+	t.Helper()
+
+	var lines strings.Builder
+	for len(data) > 0 {
+		if len(data) < 6 {
+			t.Fatalf("truncated frame header")
+		}
+		wireId := binary.LittleEndian.Uint16(data[0:2])
+		length := int(binary.LittleEndian.Uint32(data[2:6]))
+		if len(data) < 6+length {
+			t.Fatalf("truncated frame payload")
+		}
+
+		payload, err := internal.DecompressData(data[6 : 6+length])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fmt.Fprintf(&lines, "%d %s\n", wireId, hex.EncodeToString(payload))
+		data = data[6+length:]
+	}
+	return lines.String()
+}
+
+func compareGolden(t *testing.T, path string, actual string) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if string(expected) != actual {
+		t.Fatalf("encoding does not match %s\nexpected: %s\nactual:   %s", path, expected, actual)
+	}
+}
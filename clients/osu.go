@@ -0,0 +1,284 @@
+package clients
+
+import (
+	"fmt"
+	"io"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/internal"
+)
+
+// Layout interfaces of the client versions, that the packet
+// writers of the OsuClient dispatch to
+type (
+	statusWriter interface {
+		WriteStatus(io.Writer, *chio.UserStatus) error
+	}
+	ircMessageWriter interface {
+		WriteIrcMessage(io.Writer, chio.Message) error
+	}
+	privateMessageWriter interface {
+		WritePrivateMessage(io.Writer, chio.Message) error
+	}
+	frameBundleWriter interface {
+		WriteFrameBundle(io.Writer, chio.ReplayFrameBundle) error
+	}
+	matchJoinWriter interface {
+		WriteMatchJoin(io.Writer, chio.MatchJoin) error
+	}
+	scoreFrameWriter interface {
+		WriteScoreFrame(io.Writer, *chio.ScoreFrame)
+	}
+	packetReader interface {
+		readPacket(io.Reader, chio.ReaderRegistry) (*chio.BanchoPacket, error)
+	}
+)
+
+// OsuClient implements the client side of the bancho protocol, i.e. it writes
+// Osu* packets and reads Bancho* packets. It reuses the packet layouts of the
+// BanchoIO it was created from, so that both sides always agree on the format.
+type OsuClient struct {
+	IO      chio.BanchoIO
+	Readers chio.ReaderRegistry
+}
+
+func (client *OsuClient) WritePacket(stream io.Writer, packetId uint16, data []byte) error {
+	return client.IO.WritePacket(stream, packetId, data)
+}
+
+func (client *OsuClient) ReadPacket(stream io.Reader) (*chio.BanchoPacket, error) {
+	reader, ok := client.IO.(packetReader)
+	if !ok {
+		return nil, fmt.Errorf("client does not implement readPacket")
+	}
+	return reader.readPacket(stream, client.Readers)
+}
+
+func (client *OsuClient) ImplementsPacket(packetId uint16) bool {
+	return client.IO.ImplementsPacket(packetId)
+}
+
+func (client *OsuClient) Supports(feature chio.Feature) bool {
+	return chio.Supports(client.IO, feature)
+}
+
+func (client *OsuClient) ProtocolVersion() int {
+	return client.IO.ProtocolVersion()
+}
+
+func (client *OsuClient) GetReaders() chio.ReaderRegistry {
+	return client.Readers
+}
+
+// writePacket encodes & writes a packet, if it is supported by the client version
+func (client *OsuClient) writePacket(stream io.Writer, packetId uint16, encode func(io.Writer) error) error {
+	if !client.IO.ImplementsPacket(packetId) {
+		return nil
+	}
+
+	writer := internal.GetBuffer()
+	defer internal.PutBuffer(writer)
+
+	if err := encode(writer); err != nil {
+		return err
+	}
+
+	return client.IO.WritePacket(stream, packetId, writer.Bytes())
+}
+
+func (client *OsuClient) writeEmpty(stream io.Writer, packetId uint16) error {
+	return client.writePacket(stream, packetId, func(io.Writer) error { return nil })
+}
+
+func (client *OsuClient) writeInt(stream io.Writer, packetId uint16, value int32) error {
+	return client.writePacket(stream, packetId, func(w io.Writer) error {
+		return internal.WriteInt32(w, value)
+	})
+}
+
+func (client *OsuClient) writeMatch(stream io.Writer, packetId uint16, match chio.Match) error {
+	return client.writePacket(stream, packetId, func(w io.Writer) error {
+		_, err := w.Write(client.IO.WriteMatch(match))
+		return err
+	})
+}
+
+func (client *OsuClient) WriteSendUserStatus(stream io.Writer, status chio.UserStatus) error {
+	return client.writePacket(stream, chio.OsuSendUserStatus, func(w io.Writer) error {
+		writer, ok := client.IO.(statusWriter)
+		if !ok {
+			return fmt.Errorf("client does not implement WriteStatus")
+		}
+		return writer.WriteStatus(w, &status)
+	})
+}
+
+func (client *OsuClient) WriteSendIrcMessage(stream io.Writer, message chio.Message) error {
+	return client.writePacket(stream, chio.OsuSendIrcMessage, func(w io.Writer) error {
+		writer, ok := client.IO.(ircMessageWriter)
+		if !ok {
+			return fmt.Errorf("client does not implement WriteIrcMessage")
+		}
+		return writer.WriteIrcMessage(w, message)
+	})
+}
+
+func (client *OsuClient) WriteSendIrcMessagePrivate(stream io.Writer, message chio.Message) error {
+	return client.writePacket(stream, chio.OsuSendIrcMessagePrivate, func(w io.Writer) error {
+		writer, ok := client.IO.(privateMessageWriter)
+		if !ok {
+			return fmt.Errorf("client does not implement WritePrivateMessage")
+		}
+		return writer.WritePrivateMessage(w, message)
+	})
+}
+
+func (client *OsuClient) WriteExit(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuExit)
+}
+
+func (client *OsuClient) WriteRequestStatusUpdate(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuRequestStatusUpdate)
+}
+
+func (client *OsuClient) WritePong(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuPong)
+}
+
+func (client *OsuClient) WriteStartSpectating(stream io.Writer, userId int32) error {
+	return client.writeInt(stream, chio.OsuStartSpectating, userId)
+}
+
+func (client *OsuClient) WriteStopSpectating(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuStopSpectating)
+}
+
+func (client *OsuClient) WriteSpectateFrames(stream io.Writer, bundle chio.ReplayFrameBundle) error {
+	return client.writePacket(stream, chio.OsuSpectateFrames, func(w io.Writer) error {
+		writer, ok := client.IO.(frameBundleWriter)
+		if !ok {
+			return fmt.Errorf("client does not implement WriteFrameBundle")
+		}
+		return writer.WriteFrameBundle(w, bundle)
+	})
+}
+
+func (client *OsuClient) WriteErrorReport(stream io.Writer, text string) error {
+	return client.writePacket(stream, chio.OsuErrorReport, func(w io.Writer) error {
+		return internal.WriteString(w, text)
+	})
+}
+
+func (client *OsuClient) WriteCantSpectate(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuCantSpectate)
+}
+
+func (client *OsuClient) WriteLobbyPart(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuLobbyPart)
+}
+
+func (client *OsuClient) WriteLobbyJoin(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuLobbyJoin)
+}
+
+func (client *OsuClient) WriteMatchCreate(stream io.Writer, match chio.Match) error {
+	return client.writeMatch(stream, chio.OsuMatchCreate, match)
+}
+
+func (client *OsuClient) WriteMatchJoin(stream io.Writer, join chio.MatchJoin) error {
+	return client.writePacket(stream, chio.OsuMatchJoin, func(w io.Writer) error {
+		writer, ok := client.IO.(matchJoinWriter)
+		if !ok {
+			return fmt.Errorf("client does not implement WriteMatchJoin")
+		}
+		return writer.WriteMatchJoin(w, join)
+	})
+}
+
+func (client *OsuClient) WriteMatchPart(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuMatchPart)
+}
+
+func (client *OsuClient) WriteMatchChangeSlot(stream io.Writer, slotId int32) error {
+	return client.writeInt(stream, chio.OsuMatchChangeSlot, slotId)
+}
+
+func (client *OsuClient) WriteMatchReady(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuMatchReady)
+}
+
+func (client *OsuClient) WriteMatchLock(stream io.Writer, slotId int32) error {
+	return client.writeInt(stream, chio.OsuMatchLock, slotId)
+}
+
+func (client *OsuClient) WriteMatchChangeSettings(stream io.Writer, match chio.Match) error {
+	return client.writeMatch(stream, chio.OsuMatchChangeSettings, match)
+}
+
+func (client *OsuClient) WriteMatchStart(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuMatchStart)
+}
+
+func (client *OsuClient) WriteMatchScoreUpdate(stream io.Writer, frame chio.ScoreFrame) error {
+	return client.writePacket(stream, chio.OsuMatchScoreUpdate, func(w io.Writer) error {
+		writer, ok := client.IO.(scoreFrameWriter)
+		if !ok {
+			return fmt.Errorf("client does not implement WriteScoreFrame")
+		}
+		writer.WriteScoreFrame(w, &frame)
+		return nil
+	})
+}
+
+func (client *OsuClient) WriteMatchComplete(stream io.Writer) error {
+	return client.writeEmpty(stream, chio.OsuMatchComplete)
+}
+
+func (client *OsuClient) WriteMatchChangeBeatmap(stream io.Writer, match chio.Match) error {
+	return client.writeMatch(stream, chio.OsuMatchChangeBeatmap, match)
+}
+
+// NewOsuClient creates the client side for the provided protocol implementation
+func NewOsuClient(io chio.BanchoIO) *OsuClient {
+	client := &OsuClient{IO: io, Readers: make(chio.ReaderRegistry)}
+
+	// Packets that are not supported by the version are
+	// rejected in ReadPacket, before reaching the readers
+	client.Readers[chio.BanchoLoginReply] = internal.ReaderReadLoginReply()
+	client.Readers[chio.BanchoCommandError] = internal.ReaderReadEmpty()
+	client.Readers[chio.BanchoSendMessage] = internal.ReaderReadBanchoMessage()
+	client.Readers[chio.BanchoPing] = internal.ReaderReadEmpty()
+	client.Readers[chio.BanchoHandleIrcChangeUsername] = internal.ReaderReadIrcChangeUsername()
+	client.Readers[chio.BanchoHandleIrcQuit] = internal.ReaderReadIrcQuit()
+	client.Readers[chio.BanchoHandleIrcJoin] = internal.ReaderReadIrcJoin()
+	client.Readers[chio.BanchoHandleOsuUpdate] = internal.ReaderReadUserStats()
+	client.Readers[chio.BanchoHandleOsuQuit] = internal.ReaderReadUserQuit()
+	client.Readers[chio.BanchoSpectatorJoined] = internal.ReaderReadSpectatorJoined()
+	client.Readers[chio.BanchoSpectatorLeft] = internal.ReaderReadSpectatorLeft()
+	client.Readers[chio.BanchoSpectateFrames] = internal.ReaderReadFrameBundle()
+	client.Readers[chio.BanchoVersionUpdate] = internal.ReaderReadEmpty()
+	client.Readers[chio.BanchoSpectatorCantSpectate] = internal.ReaderReadSpectatorCantSpectate()
+	client.Readers[chio.BanchoGetAttention] = internal.ReaderReadEmpty()
+	client.Readers[chio.BanchoAnnounce] = internal.ReaderReadAnnouncement()
+	client.Readers[chio.BanchoMatchUpdate] = internal.ReaderReadMatch()
+	client.Readers[chio.BanchoMatchNew] = internal.ReaderReadMatch()
+	client.Readers[chio.BanchoMatchDisband] = internal.ReaderReadMatchDisband()
+	client.Readers[chio.BanchoLobbyJoin] = internal.ReaderReadLobbyJoin()
+	client.Readers[chio.BanchoLobbyPart] = internal.ReaderReadLobbyPart()
+	client.Readers[chio.BanchoMatchJoinSuccess] = internal.ReaderReadMatch()
+	client.Readers[chio.BanchoMatchJoinFail] = internal.ReaderReadEmpty()
+	client.Readers[chio.BanchoFellowSpectatorJoined] = internal.ReaderReadFellowSpectatorJoined()
+	client.Readers[chio.BanchoFellowSpectatorLeft] = internal.ReaderReadFellowSpectatorLeft()
+	client.Readers[chio.BanchoMatchStart] = internal.ReaderReadEmpty()
+	client.Readers[chio.BanchoMatchScoreUpdate] = internal.ReaderReadScoreFrame()
+	return client
+}
+
+// GetOsuInterface returns the client side of the protocol for the given client version
+func GetOsuInterface(clientVersion int) chio.OsuIO {
+	io := chio.GetClientInterface(clientVersion)
+	if io == nil {
+		return nil
+	}
+	return NewOsuClient(io)
+}
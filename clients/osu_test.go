@@ -0,0 +1,564 @@
+package clients
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+// serverWriter calls one of the BanchoWriters, and checks what the OsuClient reads from it
+type serverWriter struct {
+	packets []uint16 // Packets that the writer may write, depending on the version
+	write   func(server chio.BanchoIO, stream io.Writer) error
+	check   func(t *testing.T, packet *chio.BanchoPacket)
+
+	// Whether the packet is written without the payload of its metadata
+	empty bool
+}
+
+func writesInt(packetId uint16, write func(server chio.BanchoIO, stream io.Writer, value int32) error) serverWriter {
+	return serverWriter{
+		packets: []uint16{packetId},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return write(server, stream, 3) },
+	}
+}
+
+func writesEmpty(packetId uint16, write func(server chio.BanchoIO, stream io.Writer) error) serverWriter {
+	return serverWriter{packets: []uint16{packetId}, write: write}
+}
+
+func checkUserId[T any](id func(T) int32) func(t *testing.T, packet *chio.BanchoPacket) {
+	return func(t *testing.T, packet *chio.BanchoPacket) {
+		if data := decodeGolden[T](t, packet); id(data) != 3 {
+			t.Errorf("decoded %+v", data)
+		}
+	}
+}
+
+func checkMessage(t *testing.T, packet *chio.BanchoPacket) {
+	if message := decodeGolden[chio.Message](t, packet); message.Content != "Hello, World!" {
+		t.Errorf("decoded %+v", message)
+	}
+}
+
+func checkUserInfo(t *testing.T, packet *chio.BanchoPacket) {
+	if info := decodeGolden[chio.UserInfo](t, packet); info.Id != 2 {
+		t.Errorf("decoded %+v", info)
+	}
+}
+
+func checkChannel(t *testing.T, packet *chio.BanchoPacket) {
+	if channel := decodeGolden[chio.Channel](t, packet); channel.Name != "#osu" {
+		t.Errorf("decoded %+v", channel)
+	}
+}
+
+var serverWriters = map[string]serverWriter{
+	"WriteLoginReply": {
+		packets: []uint16{chio.BanchoLoginReply},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteLoginReply(stream, 3) },
+		check:   checkUserId(func(reply chio.LoginReply) int32 { return reply.Reply }),
+	},
+	"WriteMessage": {
+		packets: []uint16{chio.BanchoSendMessage},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteMessage(stream, goldenMessage())
+		},
+		check: checkMessage,
+	},
+	"WritePing": writesEmpty(chio.BanchoPing, chio.BanchoIO.WritePing),
+	"WriteIrcChangeUsername": {
+		packets: []uint16{chio.BanchoHandleIrcChangeUsername},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteIrcChangeUsername(stream, "peppy", "ppy")
+		},
+		check: func(t *testing.T, packet *chio.BanchoPacket) {
+			if change := decodeGolden[chio.IrcChangeUsername](t, packet); change.OldName != "peppy" || change.NewName != "ppy" {
+				t.Errorf("decoded %+v", change)
+			}
+		},
+	},
+	"WriteUserStats": {
+		packets: []uint16{chio.BanchoHandleOsuUpdate, chio.BanchoHandleIrcJoin},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteUserStats(stream, goldenUserInfo())
+		},
+		check: checkUserInfo,
+	},
+	"WriteUserQuit": {
+		packets: []uint16{chio.BanchoHandleOsuQuit, chio.BanchoHandleIrcQuit},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			info := goldenUserInfo()
+			return server.WriteUserQuit(stream, chio.UserQuit{Info: &info, QuitState: chio.QuitStateGone})
+		},
+		check: func(t *testing.T, packet *chio.BanchoPacket) {
+			if quit := decodeGolden[chio.UserQuit](t, packet); quit.Info == nil || quit.Info.Id != 2 {
+				t.Errorf("decoded %+v", quit)
+			}
+		},
+	},
+	"WriteSpectatorJoined": {
+		packets: []uint16{chio.BanchoSpectatorJoined},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteSpectatorJoined(stream, 3) },
+		check:   checkUserId(func(data chio.SpectatorJoined) int32 { return data.UserId }),
+	},
+	"WriteSpectatorLeft": {
+		packets: []uint16{chio.BanchoSpectatorLeft},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteSpectatorLeft(stream, 3) },
+		check:   checkUserId(func(data chio.SpectatorLeft) int32 { return data.UserId }),
+	},
+	"WriteSpectateFrames": {
+		packets: []uint16{chio.BanchoSpectateFrames},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteSpectateFrames(stream, goldenFrameBundle())
+		},
+		check: checkGoldenFrameBundle,
+	},
+	"WriteVersionUpdate": writesEmpty(chio.BanchoVersionUpdate, chio.BanchoIO.WriteVersionUpdate),
+	"WriteSpectatorCantSpectate": {
+		packets: []uint16{chio.BanchoSpectatorCantSpectate},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteSpectatorCantSpectate(stream, 3)
+		},
+		check: checkUserId(func(data chio.SpectatorCantSpectate) int32 { return data.UserId }),
+	},
+	"WriteGetAttention": writesEmpty(chio.BanchoGetAttention, chio.BanchoIO.WriteGetAttention),
+	"WriteAnnouncement": {
+		packets: []uint16{chio.BanchoAnnounce},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteAnnouncement(stream, "Hello, World!")
+		},
+		check: func(t *testing.T, packet *chio.BanchoPacket) {
+			if announcement := decodeGolden[chio.Announcement](t, packet); announcement.Text != "Hello, World!" {
+				t.Errorf("decoded %+v", announcement)
+			}
+		},
+	},
+	"WriteMatchUpdate": {
+		packets: []uint16{chio.BanchoMatchUpdate},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteMatchUpdate(stream, goldenMatch())
+		},
+		check: checkGoldenMatch,
+	},
+	"WriteMatchNew": {
+		packets: []uint16{chio.BanchoMatchNew},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteMatchNew(stream, goldenMatch()) },
+		check:   checkGoldenMatch,
+	},
+	"WriteMatchDisband": {
+		packets: []uint16{chio.BanchoMatchDisband},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteMatchDisband(stream, 3) },
+		check:   checkUserId(func(data chio.MatchDisband) int32 { return data.MatchId }),
+	},
+	"WriteLobbyJoin": {
+		packets: []uint16{chio.BanchoLobbyJoin},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteLobbyJoin(stream, 3) },
+		check:   checkUserId(func(data chio.LobbyJoin) int32 { return data.UserId }),
+	},
+	"WriteLobbyPart": {
+		packets: []uint16{chio.BanchoLobbyPart},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteLobbyPart(stream, 3) },
+		check:   checkUserId(func(data chio.LobbyPart) int32 { return data.UserId }),
+	},
+	"WriteMatchJoinSuccess": {
+		packets: []uint16{chio.BanchoMatchJoinSuccess},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteMatchJoinSuccess(stream, goldenMatch())
+		},
+		check: checkGoldenMatch,
+	},
+	"WriteMatchJoinFail": writesEmpty(chio.BanchoMatchJoinFail, chio.BanchoIO.WriteMatchJoinFail),
+	"WriteFellowSpectatorJoined": {
+		packets: []uint16{chio.BanchoFellowSpectatorJoined},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteFellowSpectatorJoined(stream, 3)
+		},
+		check: checkUserId(func(data chio.FellowSpectatorJoined) int32 { return data.UserId }),
+	},
+	"WriteFellowSpectatorLeft": {
+		packets: []uint16{chio.BanchoFellowSpectatorLeft},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteFellowSpectatorLeft(stream, 3) },
+		check:   checkUserId(func(data chio.FellowSpectatorLeft) int32 { return data.UserId }),
+	},
+	"WriteMatchStart": {
+		packets: []uint16{chio.BanchoMatchStart},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteMatchStart(stream, goldenMatch())
+		},
+		// None of the supported versions send the match along with it
+		empty: true,
+	},
+	"WriteMatchScoreUpdate": {
+		packets: []uint16{chio.BanchoMatchScoreUpdate},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteMatchScoreUpdate(stream, goldenScoreFrame())
+		},
+		check: checkGoldenScoreFrame,
+	},
+	"WriteMatchTransferHost":     writesEmpty(chio.BanchoMatchTransferHost, chio.BanchoIO.WriteMatchTransferHost),
+	"WriteMatchAllPlayersLoaded": writesEmpty(chio.BanchoMatchAllPlayersLoaded, chio.BanchoIO.WriteMatchAllPlayersLoaded),
+	"WriteMatchPlayerFailed": {
+		packets: []uint16{chio.BanchoMatchPlayerFailed},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteMatchPlayerFailed(stream, 3) },
+	},
+	"WriteMatchComplete": writesEmpty(chio.BanchoMatchComplete, chio.BanchoIO.WriteMatchComplete),
+	"WriteMatchSkip":     writesEmpty(chio.BanchoMatchSkip, chio.BanchoIO.WriteMatchSkip),
+	"WriteUnauthorized":  writesEmpty(chio.BanchoUnauthorized, chio.BanchoIO.WriteUnauthorized),
+	"WriteChannelJoinSuccess": {
+		packets: []uint16{chio.BanchoChannelJoinSuccess},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteChannelJoinSuccess(stream, "#osu")
+		},
+	},
+	"WriteChannelRevoked": {
+		packets: []uint16{chio.BanchoChannelRevoked},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteChannelRevoked(stream, "#osu") },
+	},
+	"WriteChannelAvailable": {
+		packets: []uint16{chio.BanchoChannelAvailable},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteChannelAvailable(stream, chio.Channel{Name: "#osu", Topic: "General discussion."})
+		},
+		check: checkChannel,
+	},
+	"WriteChannelAvailableAutojoin": {
+		packets: []uint16{chio.BanchoChannelAvailableAutojoin},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteChannelAvailableAutojoin(stream, chio.Channel{Name: "#osu", Topic: "General discussion."})
+		},
+		check: checkChannel,
+	},
+	"WriteBeatmapInfoReply": {
+		packets: []uint16{chio.BanchoBeatmapInfoReply},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteBeatmapInfoReply(stream, chio.BeatmapInfoReply{})
+		},
+	},
+	"WriteLoginPermissions": {
+		packets: []uint16{chio.BanchoLoginPermissions},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteLoginPermissions(stream, 1) },
+	},
+	"WriteFriendsList": {
+		packets: []uint16{chio.BanchoFriendsList},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteFriendsList(stream, []int32{2, 3})
+		},
+	},
+	"WriteProtocolNegotiation": writesInt(chio.BanchoProtocolNegotiation, chio.BanchoIO.WriteProtocolNegotiation),
+	"WriteTitleUpdate": {
+		packets: []uint16{chio.BanchoTitleUpdate},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteTitleUpdate(stream, chio.TitleUpdate{ImageUrl: "https://osu.ppy.sh/title.png"})
+		},
+	},
+	"WriteMonitor":            writesEmpty(chio.BanchoMonitor, chio.BanchoIO.WriteMonitor),
+	"WriteMatchPlayerSkipped": writesInt(chio.BanchoMatchPlayerSkipped, chio.BanchoIO.WriteMatchPlayerSkipped),
+	"WriteUserPresence": {
+		packets: []uint16{chio.BanchoUserPresence, chio.BanchoHandleOsuUpdate, chio.BanchoHandleIrcJoin},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteUserPresence(stream, goldenUserInfo())
+		},
+		check: checkUserInfo,
+	},
+	"WriteRestart": writesInt(chio.BanchoRestart, chio.BanchoIO.WriteRestart),
+	"WriteInvite": {
+		packets: []uint16{chio.BanchoInvite},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteInvite(stream, goldenMessage()) },
+		check:   checkMessage,
+	},
+	"WriteChannelInfoComplete": writesEmpty(chio.BanchoChannelInfoComplete, chio.BanchoIO.WriteChannelInfoComplete),
+	"WriteMatchChangePassword": {
+		packets: []uint16{chio.BanchoMatchChangePassword},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteMatchChangePassword(stream, "secret")
+		},
+	},
+	"WriteSilenceInfo": writesInt(chio.BanchoSilenceInfo, chio.BanchoIO.WriteSilenceInfo),
+	"WriteUserSilenced": {
+		packets: []uint16{chio.BanchoUserSilenced},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteUserSilenced(stream, 3) },
+	},
+	"WriteUserPresenceSingle": {
+		packets: []uint16{chio.BanchoUserPresenceSingle, chio.BanchoUserPresence, chio.BanchoHandleOsuUpdate, chio.BanchoHandleIrcJoin},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteUserPresenceSingle(stream, goldenUserInfo())
+		},
+		check: checkUserInfo,
+	},
+	"WriteUserPresenceBundle": {
+		packets: []uint16{chio.BanchoUserPresenceBundle, chio.BanchoUserPresence, chio.BanchoHandleOsuUpdate, chio.BanchoHandleIrcJoin},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteUserPresenceBundle(stream, []chio.UserInfo{goldenUserInfo()})
+		},
+		check: checkUserInfo,
+	},
+	"WriteUserDMsBlocked": {
+		packets: []uint16{chio.BanchoUserDMsBlocked},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteUserDMsBlocked(stream, "peppy") },
+	},
+	"WriteTargetIsSilenced": {
+		packets: []uint16{chio.BanchoTargetIsSilenced},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteTargetIsSilenced(stream, "peppy")
+		},
+	},
+	"WriteVersionUpdateForced": writesEmpty(chio.BanchoVersionUpdateForced, chio.BanchoIO.WriteVersionUpdateForced),
+	"WriteSwitchServer":        writesInt(chio.BanchoSwitchServer, chio.BanchoIO.WriteSwitchServer),
+	"WriteAccountRestricted":   writesEmpty(chio.BanchoAccountRestricted, chio.BanchoIO.WriteAccountRestricted),
+	"WriteRTX": {
+		packets: []uint16{chio.BanchoRTX},
+		write:   func(server chio.BanchoIO, stream io.Writer) error { return server.WriteRTX(stream, "Hello, World!") },
+	},
+	"WriteMatchAbort": writesEmpty(chio.BanchoMatchAbort, chio.BanchoIO.WriteMatchAbort),
+	"WriteSwitchTournamentServer": {
+		packets: []uint16{chio.BanchoSwitchTournamentServer},
+		write: func(server chio.BanchoIO, stream io.Writer) error {
+			return server.WriteSwitchTournamentServer(stream, "127.0.0.1")
+		},
+	},
+}
+
+func TestServerWritersCovered(t *testing.T) {
+	names := make([]string, 0, len(serverWriters))
+	for name := range serverWriters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writers := chio.Writers()
+	sort.Strings(writers)
+
+	if !slices.Equal(names, writers) {
+		t.Fatalf("expected a case for every writer in BanchoWriters:\n%v\n%v", writers, names)
+	}
+}
+
+// TestOsuClientReadsServerWriters writes every packet with the server side of every
+// version, and checks that the client side of the same version reads it back
+func TestOsuClientReadsServerWriters(t *testing.T) {
+	names := chio.Writers()
+	sort.Strings(names)
+
+	for _, version := range chio.Versions() {
+		registered, _ := chio.DefaultRegistry.Get(version)
+		server := registered.(chio.Constructor).New()
+		server.(chio.ConfigurableIO).OverrideFallback(chio.NewFallbackPolicy(chio.FallbackDrop))
+		osu := NewOsuClient(server)
+
+		for _, name := range names {
+			writer := serverWriters[name]
+
+			t.Run(fmt.Sprintf("b%d/%s", version, name), func(t *testing.T) {
+				stream := &bytes.Buffer{}
+				if err := writer.write(server, stream); err != nil {
+					t.Fatal(err)
+				}
+
+				if stream.Len() == 0 {
+					for _, packetId := range writer.packets[:1] {
+						if server.ImplementsPacket(packetId) {
+							t.Fatalf("expected %s to be written", chio.PacketName(packetId))
+						}
+					}
+					return
+				}
+
+				for i := 0; stream.Len() > 0; i++ {
+					packet, err := osu.ReadPacket(stream)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !slices.Contains(writer.packets, packet.Id) {
+						t.Fatalf("unexpected packet %s", chio.PacketName(packet.Id))
+					}
+					if !writer.empty {
+						checkPayload(t, packet)
+					}
+
+					if i == 0 && writer.check != nil {
+						writer.check(t, packet)
+					}
+				}
+			})
+		}
+	}
+}
+
+// checkPayload checks that the packet was decoded into the payload type of its metadata
+func checkPayload(t *testing.T, packet *chio.BanchoPacket) {
+	t.Helper()
+
+	info, ok := chio.GetPacketInfo(packet.Id)
+	if !ok || info.Payload == nil {
+		return
+	}
+
+	data := reflect.TypeOf(packet.Data)
+	if data != info.Payload && data != reflect.PointerTo(info.Payload) {
+		t.Fatalf("expected %s to contain %s, got %s", info.Name, info.Payload, data)
+	}
+}
+
+// osuWriter calls one of the OsuWriters, and checks what the server reads from it
+type osuWriter struct {
+	packet uint16
+	write  func(osu chio.OsuIO, stream io.Writer) error
+	check  func(t *testing.T, packet *chio.BanchoPacket)
+}
+
+var osuWriters = map[string]osuWriter{
+	"WriteSendUserStatus": {
+		packet: chio.OsuSendUserStatus,
+		write: func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteSendUserStatus(stream, *goldenUserInfo().Status)
+		},
+		check: func(t *testing.T, packet *chio.BanchoPacket) {
+			if status := decodeGolden[chio.UserStatus](t, packet); status.BeatmapChecksum != goldenUserInfo().Status.BeatmapChecksum {
+				t.Errorf("decoded %+v", status)
+			}
+		},
+	},
+	"WriteSendIrcMessage": {
+		packet: chio.OsuSendIrcMessage,
+		write:  func(osu chio.OsuIO, stream io.Writer) error { return osu.WriteSendIrcMessage(stream, goldenMessage()) },
+		check:  checkMessage,
+	},
+	"WriteExit":                {packet: chio.OsuExit, write: chio.OsuIO.WriteExit},
+	"WriteRequestStatusUpdate": {packet: chio.OsuRequestStatusUpdate, write: chio.OsuIO.WriteRequestStatusUpdate},
+	"WritePong":                {packet: chio.OsuPong, write: chio.OsuIO.WritePong},
+	"WriteStartSpectating": {
+		packet: chio.OsuStartSpectating,
+		write:  func(osu chio.OsuIO, stream io.Writer) error { return osu.WriteStartSpectating(stream, 3) },
+		check:  checkUserId(func(data chio.StartSpectating) int32 { return data.UserId }),
+	},
+	"WriteStopSpectating": {packet: chio.OsuStopSpectating, write: chio.OsuIO.WriteStopSpectating},
+	"WriteSpectateFrames": {
+		packet: chio.OsuSpectateFrames,
+		write: func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteSpectateFrames(stream, goldenFrameBundle())
+		},
+		check: checkGoldenFrameBundle,
+	},
+	"WriteErrorReport": {
+		packet: chio.OsuErrorReport,
+		write:  func(osu chio.OsuIO, stream io.Writer) error { return osu.WriteErrorReport(stream, "Hello, World!") },
+		check: func(t *testing.T, packet *chio.BanchoPacket) {
+			if report := decodeGolden[chio.ErrorReport](t, packet); report.Text != "Hello, World!" {
+				t.Errorf("decoded %+v", report)
+			}
+		},
+	},
+	"WriteCantSpectate": {packet: chio.OsuCantSpectate, write: chio.OsuIO.WriteCantSpectate},
+	"WriteSendIrcMessagePrivate": {
+		packet: chio.OsuSendIrcMessagePrivate,
+		write: func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteSendIrcMessagePrivate(stream, chio.Message{Content: "Hello, World!", Target: "peppy"})
+		},
+		check: checkMessage,
+	},
+	"WriteLobbyPart": {packet: chio.OsuLobbyPart, write: chio.OsuIO.WriteLobbyPart},
+	"WriteLobbyJoin": {packet: chio.OsuLobbyJoin, write: chio.OsuIO.WriteLobbyJoin},
+	"WriteMatchCreate": {
+		packet: chio.OsuMatchCreate,
+		write:  func(osu chio.OsuIO, stream io.Writer) error { return osu.WriteMatchCreate(stream, goldenMatch()) },
+		check:  checkGoldenMatch,
+	},
+	"WriteMatchJoin": {
+		packet: chio.OsuMatchJoin,
+		write: func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteMatchJoin(stream, chio.MatchJoin{MatchId: 3})
+		},
+		check: checkUserId(func(data chio.MatchJoin) int32 { return data.MatchId }),
+	},
+	"WriteMatchPart": {packet: chio.OsuMatchPart, write: chio.OsuIO.WriteMatchPart},
+	"WriteMatchChangeSlot": {
+		packet: chio.OsuMatchChangeSlot,
+		write:  func(osu chio.OsuIO, stream io.Writer) error { return osu.WriteMatchChangeSlot(stream, 3) },
+		check:  checkUserId(func(data chio.MatchChangeSlot) int32 { return data.SlotId }),
+	},
+	"WriteMatchReady": {packet: chio.OsuMatchReady, write: chio.OsuIO.WriteMatchReady},
+	"WriteMatchLock": {
+		packet: chio.OsuMatchLock,
+		write:  func(osu chio.OsuIO, stream io.Writer) error { return osu.WriteMatchLock(stream, 3) },
+		check:  checkUserId(func(data chio.MatchLock) int32 { return data.SlotId }),
+	},
+	"WriteMatchChangeSettings": {
+		packet: chio.OsuMatchChangeSettings,
+		write: func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteMatchChangeSettings(stream, goldenMatch())
+		},
+		check: checkGoldenMatch,
+	},
+	"WriteMatchStart": {packet: chio.OsuMatchStart, write: chio.OsuIO.WriteMatchStart},
+	"WriteMatchScoreUpdate": {
+		packet: chio.OsuMatchScoreUpdate,
+		write: func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteMatchScoreUpdate(stream, goldenScoreFrame())
+		},
+		check: checkGoldenScoreFrame,
+	},
+	"WriteMatchComplete": {packet: chio.OsuMatchComplete, write: chio.OsuIO.WriteMatchComplete},
+	"WriteMatchChangeBeatmap": {
+		packet: chio.OsuMatchChangeBeatmap,
+		write: func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteMatchChangeBeatmap(stream, goldenMatch())
+		},
+		check: checkGoldenMatch,
+	},
+}
+
+// TestServerReadsOsuWriters writes every packet with the client side of every
+// version, and checks that the server side of the same version reads it back
+func TestServerReadsOsuWriters(t *testing.T) {
+	methods := reflect.TypeFor[chio.OsuWriters]()
+	names := make([]string, methods.NumMethod())
+	for i := range names {
+		names[i] = methods.Method(i).Name
+		if _, ok := osuWriters[names[i]]; !ok {
+			t.Fatalf("expected a case for %s", names[i])
+		}
+	}
+
+	for _, version := range chio.Versions() {
+		server := chio.GetClientInterface(version)
+		osu := NewOsuClient(server)
+
+		for _, name := range names {
+			writer := osuWriters[name]
+
+			t.Run(fmt.Sprintf("b%d/%s", version, name), func(t *testing.T) {
+				stream := &bytes.Buffer{}
+				if err := writer.write(osu, stream); err != nil {
+					t.Fatal(err)
+				}
+
+				if stream.Len() == 0 {
+					if server.ImplementsPacket(writer.packet) {
+						t.Fatalf("expected %s to be written", chio.PacketName(writer.packet))
+					}
+					return
+				}
+
+				packet, err := server.ReadPacket(stream)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if packet.Id != writer.packet || stream.Len() != 0 {
+					t.Fatalf("expected a single %s, got %s", chio.PacketName(writer.packet), chio.PacketName(packet.Id))
+				}
+				checkPayload(t, packet)
+
+				if writer.check != nil {
+					writer.check(t, packet)
+				}
+			})
+		}
+	}
+}
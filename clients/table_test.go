@@ -0,0 +1,76 @@
+package clients
+
+import (
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+func TestPacketTablesAreBijective(t *testing.T) {
+	versions := map[int]chio.BanchoIO{
+		282: NewB282(),
+		291: NewB291(),
+		294: NewB294(),
+		296: NewB296(),
+		298: NewB298(),
+		312: NewB312(),
+		320: NewB320(),
+		323: NewB323(),
+	}
+
+	for version, client := range versions {
+		wireIds := make(map[uint16]uint16)
+
+		for _, packetId := range client.SupportedPackets() {
+			wireId := client.ConvertOutputPacketId(packetId)
+			if other, ok := wireIds[wireId]; ok {
+				t.Errorf("b%d: %s and %s both use wire id %d",
+					version, chio.PacketName(other), chio.PacketName(packetId), wireId)
+			}
+			wireIds[wireId] = packetId
+
+			if back := client.ConvertInputPacketId(wireId); back != packetId {
+				t.Errorf("b%d: %s converts to wire id %d, which converts back to %s",
+					version, chio.PacketName(packetId), wireId, chio.PacketName(back))
+			}
+		}
+	}
+}
+
+func TestPacketTableWireIds(t *testing.T) {
+	tests := []struct {
+		version  int
+		client   chio.BanchoIO
+		packetId uint16
+		wireId   uint16
+	}{
+		{282, NewB282(), chio.BanchoHandleIrcQuit, 10},
+		{282, NewB282(), chio.BanchoHandleIrcJoin, 11},
+		{282, NewB282(), chio.BanchoHandleOsuUpdate, 12},
+		{312, NewB312(), chio.OsuMatchStart, 45},
+		{312, NewB312(), chio.BanchoMatchStart, 46},
+		{323, NewB323(), chio.OsuMatchChangeBeatmap, 50},
+	}
+
+	for _, test := range tests {
+		if wireId := test.client.ConvertOutputPacketId(test.packetId); wireId != test.wireId {
+			t.Errorf("b%d: expected %s to use wire id %d, got %d",
+				test.version, chio.PacketName(test.packetId), test.wireId, wireId)
+		}
+	}
+}
+
+func TestPacketTableValidate(t *testing.T) {
+	table := chio.NewPacketTable(map[uint16]uint16{
+		chio.OsuSendUserStatus: 0,
+		chio.OsuSendIrcMessage: 1,
+	})
+	if err := table.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	table = table.Extend(map[uint16]uint16{chio.OsuExit: 1})
+	if err := table.Validate(); err == nil {
+		t.Fatal("expected an error for a duplicate wire id")
+	}
+}
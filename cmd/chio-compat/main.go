@@ -0,0 +1,128 @@
+// chio-compat prints a compatibility matrix of every registered client version,
+// containing the supported packets with their wire ids, the features and the
+// writers that are no-ops for each version.
+//
+// Usage:
+//
+//	chio-compat > COMPATIBILITY.md
+//	chio-compat -json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	chio "github.com/Lekuruu/chio-go"
+	_ "github.com/Lekuruu/chio-go/clients"
+)
+
+func main() {
+	asJson := flag.Bool("json", false, "print the matrix as JSON instead of markdown")
+	flag.Parse()
+
+	matrix := chio.CompatibilityMatrix()
+
+	if *asJson {
+		if err := writeJson(os.Stdout, matrix); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	writeMarkdown(os.Stdout, matrix)
+}
+
+func writeJson(w io.Writer, matrix []*chio.Compatibility) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(matrix)
+}
+
+// writeMarkdown writes one table for the packets and one for the writers, with a column per version
+func writeMarkdown(w io.Writer, matrix []*chio.Compatibility) {
+	fmt.Fprintln(w, "# Compatibility")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Packets")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Cells contain the id that is used on the wire, and are marked with `*` if it differs from the packet id.")
+	fmt.Fprintln(w)
+	writeHeader(w, "Packet", matrix)
+
+	for _, info := range chio.Packets() {
+		cells := make([]string, len(matrix))
+		supported := false
+
+		for i, compat := range matrix {
+			packet, ok := compat.Supports(info.Id)
+			if !ok {
+				cells[i] = "-"
+				continue
+			}
+
+			supported = true
+			cells[i] = fmt.Sprintf("%d", packet.WireId)
+			if packet.Translated() {
+				cells[i] += "*"
+			}
+		}
+
+		if supported {
+			writeRow(w, fmt.Sprintf("%s (%d)", info.Name, info.Id), cells)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Features")
+	fmt.Fprintln(w)
+	writeHeader(w, "Feature", matrix)
+
+	for _, feature := range chio.Features() {
+		cells := make([]string, len(matrix))
+
+		for i, compat := range matrix {
+			cells[i] = "-"
+			if compat.HasFeature(feature) {
+				cells[i] = "yes"
+			}
+		}
+
+		writeRow(w, feature.String(), cells)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Writers")
+	fmt.Fprintln(w)
+	writeHeader(w, "Writer", matrix)
+
+	for _, writer := range chio.Writers() {
+		cells := make([]string, len(matrix))
+
+		for i, compat := range matrix {
+			cells[i] = "yes"
+			if compat.IsNoop(writer) {
+				cells[i] = "no-op"
+			}
+		}
+
+		writeRow(w, writer, cells)
+	}
+}
+
+func writeHeader(w io.Writer, title string, matrix []*chio.Compatibility) {
+	columns := make([]string, len(matrix))
+	for i, compat := range matrix {
+		columns[i] = fmt.Sprintf("b%d", compat.Version)
+	}
+
+	writeRow(w, title, columns)
+	fmt.Fprintf(w, "|---|%s\n", strings.Repeat("---|", len(matrix)))
+}
+
+func writeRow(w io.Writer, title string, cells []string) {
+	fmt.Fprintf(w, "| %s | %s |\n", title, strings.Join(cells, " | "))
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	output := new(bytes.Buffer)
+	writeMarkdown(output, chio.CompatibilityMatrix())
+
+	expected := []string{
+		"| Packet | b282 | b290 | b291 | b294 | b296 | b298 | b312 | b320 | b323 |",
+		"| BanchoHandleOsuUpdate (11) | 12* | 12* |",
+		"| MessageTargets | - | - | - | - | - | - | - | yes | yes |",
+		"| WriteAnnouncement | no-op | no-op | yes | yes |",
+	}
+	for _, line := range expected {
+		if !strings.Contains(output.String(), line) {
+			t.Errorf("expected the markdown to contain %q", line)
+		}
+	}
+}
+
+func TestWriteJson(t *testing.T) {
+	output := new(bytes.Buffer)
+	if err := writeJson(output, chio.CompatibilityMatrix()); err != nil {
+		t.Fatal(err)
+	}
+
+	var matrix []*chio.Compatibility
+	if err := json.Unmarshal(output.Bytes(), &matrix); err != nil {
+		t.Fatal(err)
+	}
+	if len(matrix) != len(chio.Versions()) {
+		t.Fatalf("expected %d versions, got %d", len(chio.Versions()), len(matrix))
+	}
+
+	compat := matrix[0]
+	if compat.Version != 282 || !compat.IsNoop("WriteAnnouncement") || compat.HasFeature(chio.FeatureAnnouncements) {
+		t.Fatalf("unexpected compatibility for b282: %+v", compat)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+	"github.com/Lekuruu/chio-go/internal"
+)
+
+// Frame is a single dissected packet
+type Frame struct {
+	Offset    int    `json:"offset"`
+	WireId    uint16 `json:"wire_id"`
+	Id        uint16 `json:"id"`
+	Name      string `json:"name"`
+	Direction string `json:"direction"`
+	Length    int    `json:"length"`
+	Gzip      bool   `json:"gzip"`
+	Data      any    `json:"data,omitempty"`
+	Trailing  int    `json:"trailing,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Dissector splits raw bytes into frames and decodes them with the readers of a client version
+type Dissector struct {
+	IO            chio.BanchoIO
+	ServerReaders chio.ReaderRegistry // Readers for packets sent by the client
+	ClientReaders chio.ReaderRegistry // Readers for packets sent by the server
+}
+
+func NewDissector(version int) (*Dissector, error) {
+	client := chio.GetClientInterface(version)
+	if client == nil {
+		return nil, errors.New("no client versions registered")
+	}
+
+	return &Dissector{
+		IO:            client,
+		ServerReaders: client.GetReaders(),
+		ClientReaders: clients.NewOsuClient(client).GetReaders(),
+	}, nil
+}
+
+// Dissect decodes every frame in data. It stops at the first frame that
+// is cut off, which is returned with an error describing the missing bytes.
+func (d *Dissector) Dissect(data []byte) []Frame {
+	frames := make([]Frame, 0)
+	offset := 0
+
+	for offset < len(data) {
+		frame, size := d.dissectFrame(data[offset:])
+		frame.Offset = offset
+		frames = append(frames, frame)
+
+		if size == 0 {
+			break
+		}
+		offset += size
+	}
+
+	return frames
+}
+
+// dissectFrame decodes the frame at the start of data, and returns its size on the wire
+func (d *Dissector) dissectFrame(data []byte) (Frame, int) {
+	frame := Frame{}
+	reader := bytes.NewReader(data)
+
+	wireId, err := internal.ReadUint16(reader)
+	if err != nil {
+		frame.Error = fmt.Sprintf("truncated header: %d trailing bytes", len(data))
+		return frame, 0
+	}
+
+	frame.WireId = wireId
+	frame.Id = d.IO.ConvertInputPacketId(wireId)
+	frame.Name = chio.PacketName(frame.Id)
+	direction := chio.PacketDirection(frame.Id)
+	frame.Direction = direction.String()
+
+	length, err := internal.ReadUint32(reader)
+	if err != nil {
+		frame.Error = fmt.Sprintf("truncated header: %d trailing bytes", len(data))
+		return frame, 0
+	}
+	frame.Length = int(length)
+
+	if int(length) > reader.Len() {
+		frame.Error = fmt.Sprintf("truncated payload: expected %d bytes, got %d", length, reader.Len())
+		return frame, 0
+	}
+
+	size := 6 + int(length)
+	payload := data[6:size]
+
+	// Unknown wire ids are passed through by the conversion,
+	// so the packet only counts if it converts back the same way
+	if !d.IO.ImplementsPacket(frame.Id) || d.IO.ConvertOutputPacketId(frame.Id) != wireId {
+		frame.Error = "packet is not implemented in this version"
+		return frame, size
+	}
+
+	// The compression policy of the sender is unknown, so like
+	// ReadPacket, the gzip header tells compressed payloads apart
+	if internal.IsCompressed(payload) {
+		frame.Gzip = true
+		payload, err = internal.DecompressData(payload)
+		if err != nil {
+			frame.Error = fmt.Sprintf("decompression failed: %v", err)
+			return frame, size
+		}
+	}
+
+	var readers chio.ReaderRegistry
+	switch direction {
+	case chio.DirectionClientToServer:
+		readers = d.ServerReaders
+	case chio.DirectionServerToClient:
+		readers = d.ClientReaders
+	default:
+		// Without metadata, it's unknown which side's readers would apply
+		frame.Trailing = len(payload)
+		frame.Error = "unknown packet direction"
+		return frame, size
+	}
+
+	read, ok := readers[frame.Id]
+	if !ok {
+		frame.Trailing = len(payload)
+		if frame.Trailing > 0 {
+			frame.Error = "no reader registered for this packet"
+		}
+		return frame, size
+	}
+
+	payloadReader := bytes.NewReader(payload)
+	frame.Data, err = read(d.IO, payloadReader)
+	frame.Trailing = payloadReader.Len()
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		frame.Error = "payload is shorter than expected"
+	} else if err != nil {
+		frame.Error = err.Error()
+	}
+
+	return frame, size
+}
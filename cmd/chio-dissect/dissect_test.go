@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+func TestDissectCompression(t *testing.T) {
+	dissector, err := NewDissector(323)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []chio.CompressionMode{chio.CompressAlways, chio.CompressNone} {
+		t.Run(mode.String(), func(t *testing.T) {
+			client := clients.NewB323()
+			client.OverrideCompression(chio.NewCompressionPolicy(mode))
+
+			stream := &bytes.Buffer{}
+			client.WriteLoginReply(stream, 1000)
+
+			frames := dissector.Dissect(stream.Bytes())
+			if len(frames) != 1 || frames[0].Error != "" {
+				t.Fatalf("expected a single valid frame, got %+v", frames)
+			}
+			if frames[0].Gzip != (mode == chio.CompressAlways) {
+				t.Fatalf("expected gzip to be %v", mode == chio.CompressAlways)
+			}
+			if reply, ok := frames[0].Data.(*chio.LoginReply); !ok || reply.Reply != 1000 {
+				t.Fatalf("expected a login reply of 1000, got %v", frames[0].Data)
+			}
+		})
+	}
+}
+
+// customClient implements a packet id that has no metadata
+type customClient struct {
+	*clients.B323
+}
+
+func (client *customClient) ImplementsPacket(packetId uint16) bool {
+	return packetId == 1337 || client.B323.ImplementsPacket(packetId)
+}
+
+func TestDissectUnknownDirection(t *testing.T) {
+	client := &customClient{clients.NewB323()}
+	dissector := &Dissector{
+		IO:            client,
+		ServerReaders: client.GetReaders(),
+		ClientReaders: clients.NewOsuClient(client).GetReaders(),
+	}
+
+	frames := dissector.Dissect([]byte{0x39, 0x05, 2, 0, 0, 0, 1, 2})
+	if len(frames) != 1 {
+		t.Fatalf("expected a single frame, got %+v", frames)
+	}
+	if frames[0].Direction != "unknown" || frames[0].Error != "unknown packet direction" || frames[0].Data != nil {
+		t.Fatalf("expected the frame to not be decoded, got %+v", frames[0])
+	}
+}
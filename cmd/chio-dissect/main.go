@@ -0,0 +1,113 @@
+// chio-dissect decodes raw bancho traffic, e.g. from a packet capture,
+// and prints every packet it contains as text or JSON.
+//
+// Usage:
+//
+//	chio-dissect -version 294 0b0003000000...
+//	chio-dissect -version 294 -file session.bin -json
+//	xxd -p session.bin | chio-dissect -version 294 -hex
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	_ "github.com/Lekuruu/chio-go/clients"
+)
+
+func main() {
+	version := flag.Int("version", 282, "client version used to decode the packets")
+	file := flag.String("file", "", "read the input from a file instead of stdin")
+	isHex := flag.Bool("hex", false, "treat the file or stdin input as a hex string")
+	asJson := flag.Bool("json", false, "print the packets as JSON")
+	flag.Parse()
+
+	data, err := readInput(flag.Arg(0), *file, *isHex)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dissector, err := NewDissector(*version)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	frames := dissector.Dissect(data)
+
+	if *asJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(frames); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	for _, frame := range frames {
+		printFrame(frame)
+	}
+}
+
+func readInput(argument string, file string, isHex bool) ([]byte, error) {
+	if argument != "" {
+		return decodeHex(argument)
+	}
+
+	var input []byte
+	var err error
+
+	if file != "" {
+		input, err = os.ReadFile(file)
+	} else {
+		input, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isHex {
+		return decodeHex(string(input))
+	}
+	return input, nil
+}
+
+func decodeHex(input string) ([]byte, error) {
+	input = strings.Join(strings.Fields(input), "")
+	input = strings.TrimPrefix(input, "0x")
+	return hex.DecodeString(input)
+}
+
+func printFrame(frame Frame) {
+	compression := ""
+	if frame.Gzip {
+		compression = ", gzip"
+	}
+
+	fmt.Printf("[%06x] %s (%d, wire %d) %s, %d bytes%s\n",
+		frame.Offset, frame.Name, frame.Id, frame.WireId, frame.Direction, frame.Length, compression)
+
+	if frame.Data != nil {
+		fmt.Printf("         %s\n", formatData(frame.Data))
+	}
+	if frame.Trailing > 0 {
+		fmt.Printf("         WARNING: %d trailing bytes in payload\n", frame.Trailing)
+	}
+	if frame.Error != "" {
+		fmt.Printf("         ERROR: %s\n", frame.Error)
+	}
+}
+
+func formatData(data any) string {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprintf("%+v", data)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,166 @@
+// chio-proxy relays connections of a client to a bancho server that speaks
+// another protocol version. Packets are decoded with the BanchoIO of one
+// version and re-encoded with the other, dropping what can't be translated.
+//
+// Usage:
+//
+//	chio-proxy -listen :13381 -upstream localhost:13382 -client 294 -server 323
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+// Session is a single proxied connection
+type Session struct {
+	Username   string
+	Downstream chio.BanchoIO // Server side of the client's version
+	Upstream   chio.OsuIO    // Client side of the server's version
+	Server     chio.BanchoIO // Server side of the server's version, used to convert frames
+
+	// Users that were seen through stats updates, used to complete
+	// status-only updates for versions that always require stats
+	users map[int32]chio.UserInfo
+}
+
+// NewSession creates a session for a client that is spoken to with the downstream
+// BanchoIO, and relayed to a server that is spoken to with the client side of server
+func NewSession(downstream chio.BanchoIO, server chio.BanchoIO) *Session {
+	return &Session{
+		Downstream: downstream,
+		Upstream:   clients.NewOsuClient(server),
+		Server:     server,
+		users:      make(map[int32]chio.UserInfo),
+	}
+}
+
+func main() {
+	listen := flag.String("listen", ":13381", "address to accept client connections on")
+	upstream := flag.String("upstream", "localhost:13382", "address of the upstream bancho server")
+	clientVersion := flag.Int("client", 294, "protocol version of the connecting clients")
+	serverVersion := flag.Int("server", 323, "protocol version spoken by the upstream server")
+	flag.Parse()
+
+	// Clients that are older than every registered version can't be translated
+	downstreamIO, err := chio.ResolveClientInterface(*clientVersion, chio.PolicyNearestLower)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	serverIO := chio.GetClientInterface(*serverVersion)
+	if serverIO == nil {
+		log.Fatal("no client versions registered")
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Relaying b%d clients on %s to b%d server at %s", *clientVersion, *listen, *serverVersion, *upstream)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Println("Failed to accept connection:", err)
+			continue
+		}
+
+		session := NewSession(downstreamIO, serverIO)
+		go session.Handle(conn, *upstream, *serverVersion)
+	}
+}
+
+// Handle relays the login and all following packets between the client & server
+func (session *Session) Handle(client net.Conn, address string, serverVersion int) {
+	defer client.Close()
+
+	server, err := net.Dial("tcp", address)
+	if err != nil {
+		log.Println("Failed to connect to upstream:", err)
+		return
+	}
+	defer server.Close()
+
+	clientReader := bufio.NewReader(client)
+	if err := session.relayLogin(clientReader, server, serverVersion); err != nil {
+		log.Println("Failed to relay login:", err)
+		return
+	}
+
+	done := make(chan struct{}, 2)
+
+	go func() {
+		session.relay(clientReader, server, session.Downstream.ReadPacket, session.forwardToServer)
+		done <- struct{}{}
+	}()
+	go func() {
+		session.relay(server, client, session.Upstream.ReadPacket, session.forwardToClient)
+		done <- struct{}{}
+	}()
+
+	// Closing both connections once either side
+	// is gone will also stop the other relay
+	<-done
+}
+
+// relayLogin forwards the login request, which consists of the username, password
+// and client info lines. The client build is replaced with the server's version.
+func (session *Session) relayLogin(client *bufio.Reader, server io.Writer, serverVersion int) error {
+	lines := make([]string, 3)
+
+	for i := range lines {
+		line, err := client.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		lines[i] = strings.TrimRight(line, "\r\n")
+	}
+
+	session.Username = lines[0]
+	_, info, _ := strings.Cut(lines[2], "|")
+	lines[2] = fmt.Sprintf("b%d", serverVersion)
+
+	if info != "" {
+		lines[2] += "|" + info
+	}
+
+	_, err := io.WriteString(server, strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
+
+// relay reads packets from src and writes their translation to dst until either side fails
+func (session *Session) relay(
+	src io.Reader,
+	dst io.Writer,
+	read func(io.Reader) (*chio.BanchoPacket, error),
+	forward func(io.Writer, *chio.BanchoPacket) error,
+) {
+	for {
+		packet, err := read(src)
+		if errors.Is(err, chio.ErrNotImplemented) {
+			// Packet can't be translated, but the stream is still intact
+			continue
+		}
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("<%s> Failed to read packet: %v", session.Username, err)
+			}
+			return
+		}
+
+		if err := forward(dst, packet); err != nil {
+			log.Printf("<%s> Failed to forward %s: %v", session.Username, chio.PacketName(packet.Id), err)
+			return
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/chiotest"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+func userInfo(status chio.UserStatus) chio.UserInfo {
+	return chio.UserInfo{
+		Id:       2,
+		Name:     "peppy",
+		Presence: &chio.UserPresence{Timezone: 1, CountryIndex: 14, City: "Perth"},
+		Status:   &status,
+		Stats:    &chio.UserStats{Rank: 1, Rscore: 1234567, Tscore: 7654321, Accuracy: 0.9876, Playcount: 42},
+	}
+}
+
+func TestRelayLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		login    string
+		expected string
+	}{
+		{"client info", "peppy\r\nhash\r\nb294|1|0|abc:def:|0\r\n", "peppy\r\nhash\r\nb323|1|0|abc:def:|0\r\n"},
+		{"build only", "peppy\nhash\nb294\n", "peppy\r\nhash\r\nb323\r\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := NewSession(clients.NewB294(), clients.NewB323())
+			server := &bytes.Buffer{}
+
+			if err := session.relayLogin(bufio.NewReader(strings.NewReader(test.login)), server, 323); err != nil {
+				t.Fatal(err)
+			}
+			if server.String() != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, server.String())
+			}
+			if session.Username != "peppy" {
+				t.Fatalf("expected the username to be remembered, got %q", session.Username)
+			}
+		})
+	}
+
+	session := NewSession(clients.NewB294(), clients.NewB323())
+	if err := session.relayLogin(bufio.NewReader(strings.NewReader("peppy\r\n")), io.Discard, 323); err == nil {
+		t.Fatal("expected an error for an incomplete login")
+	}
+}
+
+// TestRelayToServer relays the login of a b294 client, and the packets that follow
+// it in the same stream, to a b323 server
+func TestRelayToServer(t *testing.T) {
+	downstream := clients.NewB294()
+	server := clients.NewB323()
+	session := NewSession(downstream, server)
+
+	client := chiotest.NewClient(downstream)
+	io.WriteString(client, "peppy\r\nhash\r\nb294|1|0|abc:def:|0\r\n")
+	client.Script(
+		func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteSendUserStatus(stream, chio.UserStatus{Action: chio.StatusIdle})
+		},
+		func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteSendIrcMessage(stream, chio.Message{Content: "Hello!", Target: "#osu"})
+		},
+		func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteExit(stream)
+		},
+	)
+
+	reader := bufio.NewReader(client)
+	login := &bytes.Buffer{}
+	if err := session.relayLogin(reader, login, 323); err != nil {
+		t.Fatal(err)
+	}
+
+	upstream := chiotest.NewClient(server)
+	session.relay(reader, upstream, session.Downstream.ReadPacket, session.forwardToServer)
+
+	packets, err := upstream.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packets) != 3 {
+		t.Fatalf("expected 3 packets, got %v", packets)
+	}
+
+	expected := []uint16{chio.OsuSendUserStatus, chio.OsuSendIrcMessage, chio.OsuExit}
+	for i, packet := range packets {
+		if packet.Id != expected[i] {
+			t.Fatalf("expected %s, got %s", chio.PacketName(expected[i]), packet)
+		}
+	}
+	if message, _ := chio.Decode[chio.Message](packets[1]); message.Content != "Hello!" || message.Target != "#osu" {
+		t.Fatalf("unexpected message %+v", message)
+	}
+}
+
+// TestRelayToClient relays the stats, status-only updates and quit of a user from a
+// b323 server to a b294 client, which always expects the full stats of a user
+func TestRelayToClient(t *testing.T) {
+	downstream := clients.NewB294()
+	server := clients.NewB323()
+	session := NewSession(downstream, server)
+
+	upstream := &bytes.Buffer{}
+	playing := userInfo(chio.UserStatus{Action: chio.StatusPlaying, Text: "DISCO PRINCE", UpdateStats: true})
+	idle := chio.UserInfo{Id: 2, Presence: &chio.UserPresence{}, Status: &chio.UserStatus{Action: chio.StatusIdle}}
+	unknown := chio.UserInfo{Id: 3, Presence: &chio.UserPresence{}, Status: &chio.UserStatus{Action: chio.StatusIdle}}
+
+	server.WriteUserStats(upstream, unknown)
+	server.WriteUserStats(upstream, playing)
+	server.WriteUserStats(upstream, idle)
+	server.WriteUserQuit(upstream, chio.UserQuit{Info: &playing, QuitState: chio.QuitStateGone})
+
+	client := chiotest.NewStream(downstream)
+	session.relay(upstream, client, session.Upstream.ReadPacket, session.forwardToClient)
+	chiotest.ExpectNoError(t, client)
+
+	// The status-only update of the unknown user can't be completed, and is dropped.
+	// b294 marks stats updates through the action of the status, so only its text is kept.
+	first := chiotest.ExpectData[chio.UserInfo](t, client, chio.BanchoHandleOsuUpdate)
+	if first.Id != 2 || first.Name != "peppy" || first.Status.Text != "DISCO PRINCE" {
+		t.Fatalf("unexpected stats %+v", first)
+	}
+
+	second := chiotest.ExpectData[chio.UserInfo](t, client, chio.BanchoHandleOsuUpdate)
+	if second.Name != "peppy" || second.Stats == nil || second.Stats.Rscore != 1234567 {
+		t.Fatalf("expected the stats to be completed, got %+v", second)
+	}
+	if second.Status.Action != chio.StatusIdle {
+		t.Fatalf("expected the new status, got %+v", second.Status)
+	}
+
+	quit := chiotest.ExpectData[chio.UserQuit](t, client, chio.BanchoHandleOsuQuit)
+	if quit.Info == nil || quit.Info.Id != 2 {
+		t.Fatalf("unexpected quit %+v", quit)
+	}
+	chiotest.ExpectNone(t, client)
+
+	if _, ok := session.users[2]; ok {
+		t.Fatal("expected the user to be forgotten after quitting")
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"io"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+// forwardToServer re-encodes a packet that was read from the client,
+// using the client-side writers of the upstream version. Packets that the
+// upstream version does not support are dropped by the writers themselves.
+func (session *Session) forwardToServer(w io.Writer, packet *chio.BanchoPacket) error {
+	up := session.Upstream
+
+	switch packet.Id {
+	case chio.OsuSendUserStatus:
+		status, err := chio.Decode[chio.UserStatus](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteSendUserStatus(w, status)
+	case chio.OsuSendIrcMessage:
+		message, err := chio.Decode[chio.Message](packet)
+		if err != nil {
+			return err
+		}
+		message.Sender = session.Username
+		return up.WriteSendIrcMessage(w, message)
+	case chio.OsuSendIrcMessagePrivate:
+		message, err := chio.Decode[chio.Message](packet)
+		if err != nil {
+			return err
+		}
+		message.Sender = session.Username
+		return up.WriteSendIrcMessagePrivate(w, message)
+	case chio.OsuExit:
+		return up.WriteExit(w)
+	case chio.OsuRequestStatusUpdate:
+		return up.WriteRequestStatusUpdate(w)
+	case chio.OsuPong:
+		return up.WritePong(w)
+	case chio.OsuStartSpectating:
+		spectate, err := chio.Decode[chio.StartSpectating](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteStartSpectating(w, spectate.UserId)
+	case chio.OsuStopSpectating:
+		return up.WriteStopSpectating(w)
+	case chio.OsuSpectateFrames:
+		bundle, err := chio.Decode[chio.ReplayFrameBundle](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteSpectateFrames(w, chio.ConvertFrameBundle(bundle, session.Downstream, session.Server))
+	case chio.OsuErrorReport:
+		report, err := chio.Decode[chio.ErrorReport](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteErrorReport(w, report.Text)
+	case chio.OsuCantSpectate:
+		return up.WriteCantSpectate(w)
+	case chio.OsuLobbyPart:
+		return up.WriteLobbyPart(w)
+	case chio.OsuLobbyJoin:
+		return up.WriteLobbyJoin(w)
+	case chio.OsuMatchCreate:
+		match, err := chio.Decode[chio.Match](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteMatchCreate(w, match)
+	case chio.OsuMatchJoin:
+		join, err := chio.Decode[chio.MatchJoin](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteMatchJoin(w, join)
+	case chio.OsuMatchPart:
+		return up.WriteMatchPart(w)
+	case chio.OsuMatchChangeSlot:
+		slot, err := chio.Decode[chio.MatchChangeSlot](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteMatchChangeSlot(w, slot.SlotId)
+	case chio.OsuMatchReady:
+		return up.WriteMatchReady(w)
+	case chio.OsuMatchLock:
+		lock, err := chio.Decode[chio.MatchLock](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteMatchLock(w, lock.SlotId)
+	case chio.OsuMatchChangeSettings:
+		match, err := chio.Decode[chio.Match](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteMatchChangeSettings(w, match)
+	case chio.OsuMatchStart:
+		return up.WriteMatchStart(w)
+	case chio.OsuMatchScoreUpdate:
+		frame, err := chio.Decode[chio.ScoreFrame](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteMatchScoreUpdate(w, frame)
+	case chio.OsuMatchComplete:
+		return up.WriteMatchComplete(w)
+	case chio.OsuMatchChangeBeatmap:
+		match, err := chio.Decode[chio.Match](packet)
+		if err != nil {
+			return err
+		}
+		return up.WriteMatchChangeBeatmap(w, match)
+	}
+
+	return nil
+}
+
+// forwardToClient re-encodes a packet that was read from the upstream server,
+// using the server-side writers of the client version. The writers of older
+// versions already degrade or drop packets that the client can't display.
+func (session *Session) forwardToClient(w io.Writer, packet *chio.BanchoPacket) error {
+	down := session.Downstream
+
+	switch packet.Id {
+	case chio.BanchoLoginReply:
+		reply, err := chio.Decode[chio.LoginReply](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteLoginReply(w, reply.Reply)
+	case chio.BanchoSendMessage:
+		message, err := chio.Decode[chio.Message](packet)
+		if err != nil {
+			return err
+		}
+		if message.Target == "" {
+			// Direct messages of older versions don't include the target
+			message.Target = session.Username
+		}
+		return down.WriteMessage(w, message)
+	case chio.BanchoPing:
+		return down.WritePing(w)
+	case chio.BanchoHandleIrcChangeUsername:
+		change, err := chio.Decode[chio.IrcChangeUsername](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteIrcChangeUsername(w, change.OldName, change.NewName)
+	case chio.BanchoHandleIrcJoin, chio.BanchoHandleOsuUpdate:
+		info, err := chio.Decode[chio.UserInfo](packet)
+		if err != nil {
+			return err
+		}
+		if !session.completeUserInfo(&info) {
+			// We haven't seen the stats of this user yet
+			return nil
+		}
+		return down.WriteUserStats(w, info)
+	case chio.BanchoHandleIrcQuit, chio.BanchoHandleOsuQuit:
+		quit, err := chio.Decode[chio.UserQuit](packet)
+		if err != nil {
+			return err
+		}
+		if quit.Info == nil || !session.completeUserInfo(quit.Info) {
+			return nil
+		}
+		delete(session.users, quit.Info.Id)
+		return down.WriteUserQuit(w, quit)
+	case chio.BanchoSpectatorJoined:
+		spectator, err := chio.Decode[chio.SpectatorJoined](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteSpectatorJoined(w, spectator.UserId)
+	case chio.BanchoSpectatorLeft:
+		spectator, err := chio.Decode[chio.SpectatorLeft](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteSpectatorLeft(w, spectator.UserId)
+	case chio.BanchoSpectateFrames:
+		bundle, err := chio.Decode[chio.ReplayFrameBundle](packet)
+		if err != nil {
+			return err
+		}
+		return chio.RelayFrames(w, bundle, session.Server, down)
+	case chio.BanchoVersionUpdate:
+		return down.WriteVersionUpdate(w)
+	case chio.BanchoSpectatorCantSpectate:
+		spectator, err := chio.Decode[chio.SpectatorCantSpectate](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteSpectatorCantSpectate(w, spectator.UserId)
+	case chio.BanchoGetAttention:
+		return down.WriteGetAttention(w)
+	case chio.BanchoAnnounce:
+		announcement, err := chio.Decode[chio.Announcement](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteAnnouncement(w, announcement.Text)
+	case chio.BanchoMatchUpdate:
+		match, err := chio.Decode[chio.Match](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteMatchUpdate(w, match)
+	case chio.BanchoMatchNew:
+		match, err := chio.Decode[chio.Match](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteMatchNew(w, match)
+	case chio.BanchoMatchDisband:
+		disband, err := chio.Decode[chio.MatchDisband](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteMatchDisband(w, disband.MatchId)
+	case chio.BanchoLobbyJoin:
+		join, err := chio.Decode[chio.LobbyJoin](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteLobbyJoin(w, join.UserId)
+	case chio.BanchoLobbyPart:
+		part, err := chio.Decode[chio.LobbyPart](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteLobbyPart(w, part.UserId)
+	case chio.BanchoMatchJoinSuccess:
+		match, err := chio.Decode[chio.Match](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteMatchJoinSuccess(w, match)
+	case chio.BanchoMatchJoinFail:
+		return down.WriteMatchJoinFail(w)
+	case chio.BanchoFellowSpectatorJoined:
+		spectator, err := chio.Decode[chio.FellowSpectatorJoined](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteFellowSpectatorJoined(w, spectator.UserId)
+	case chio.BanchoFellowSpectatorLeft:
+		spectator, err := chio.Decode[chio.FellowSpectatorLeft](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteFellowSpectatorLeft(w, spectator.UserId)
+	case chio.BanchoMatchStart:
+		return down.WriteMatchStart(w, chio.Match{})
+	case chio.BanchoMatchScoreUpdate:
+		frame, err := chio.Decode[chio.ScoreFrame](packet)
+		if err != nil {
+			return err
+		}
+		return down.WriteMatchScoreUpdate(w, frame)
+	}
+
+	return nil
+}
+
+// completeUserInfo fills in the parts of a user that were left out by
+// status-only updates, using what the session has seen before. It returns
+// false if the user can't be written yet, because its stats are unknown.
+func (session *Session) completeUserInfo(info *chio.UserInfo) bool {
+	cached, ok := session.users[info.Id]
+
+	if info.Presence != nil && info.Presence.IsIrc {
+		return true
+	}
+
+	if ok {
+		if info.Name == "" {
+			info.Name = cached.Name
+		}
+		if info.Stats == nil {
+			info.Stats = cached.Stats
+		}
+		if info.Presence == nil {
+			info.Presence = cached.Presence
+		}
+		if info.Status == nil {
+			info.Status = cached.Status
+		}
+	}
+
+	if info.Stats == nil || info.Presence == nil || info.Status == nil {
+		return false
+	}
+
+	session.users[info.Id] = *info
+	return true
+}
@@ -0,0 +1,239 @@
+package chio
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// Compatibility describes what a registered client version supports
+type Compatibility struct {
+	Version int             `json:"version"`
+	Packets []PacketSupport `json:"packets"`
+
+	// Writers that don't write anything to the stream, because
+	// the packet is not supported by the version
+	NoopWriters []string `json:"noop_writers"`
+
+	// Features that are supported by the version
+	Features []string `json:"features"`
+}
+
+// PacketSupport describes a packet that is supported by a client version
+type PacketSupport struct {
+	Id     uint16 `json:"id"`
+	Name   string `json:"name"`
+	WireId uint16 `json:"wire_id"`
+}
+
+// Translated checks if the packet uses another id on the wire
+func (packet PacketSupport) Translated() bool {
+	return packet.Id != packet.WireId
+}
+
+// Supports checks if the packet is supported by this version
+func (compat *Compatibility) Supports(packetId uint16) (PacketSupport, bool) {
+	for _, packet := range compat.Packets {
+		if packet.Id == packetId {
+			return packet, true
+		}
+	}
+	return PacketSupport{}, false
+}
+
+// HasFeature checks if the feature is supported by this version
+func (compat *Compatibility) HasFeature(feature Feature) bool {
+	for _, name := range compat.Features {
+		if name == feature.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNoop checks if the writer with the given method name is a no-op in this version
+func (compat *Compatibility) IsNoop(writer string) bool {
+	for _, name := range compat.NoopWriters {
+		if name == writer {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCompatibility returns the compatibility of a single client version.
+// Unlike GetClientInterface, the version has to be registered exactly.
+func GetCompatibility(version int) (*Compatibility, bool) {
+	return DefaultRegistry.Compatibility(version)
+}
+
+// CompatibilityMatrix returns the compatibility of every registered client version
+func CompatibilityMatrix() []*Compatibility {
+	return DefaultRegistry.CompatibilityMatrix()
+}
+
+// Compatibility returns the compatibility of a single registered client version
+func (registry *Registry) Compatibility(version int) (*Compatibility, bool) {
+	client, ok := registry.Get(version)
+	if !ok {
+		return nil, false
+	}
+
+	compat := &Compatibility{
+		Version:     version,
+		Packets:     make([]PacketSupport, 0),
+		NoopWriters: NoopWriters(client),
+		Features:    make([]string, 0),
+	}
+
+	for _, feature := range ClientFeatures(client).Features() {
+		compat.Features = append(compat.Features, feature.String())
+	}
+
+	for _, packetId := range client.SupportedPackets() {
+		compat.Packets = append(compat.Packets, PacketSupport{
+			Id:     packetId,
+			Name:   PacketName(packetId),
+			WireId: client.ConvertOutputPacketId(packetId),
+		})
+	}
+
+	sort.Slice(compat.Packets, func(i, j int) bool {
+		return compat.Packets[i].Id < compat.Packets[j].Id
+	})
+
+	return compat, true
+}
+
+// CompatibilityMatrix returns the compatibility of every registered client version
+func (registry *Registry) CompatibilityMatrix() []*Compatibility {
+	versions := registry.Versions()
+	matrix := make([]*Compatibility, 0, len(versions))
+	for _, version := range versions {
+		compat, _ := registry.Compatibility(version)
+		matrix = append(matrix, compat)
+	}
+	return matrix
+}
+
+// Writers returns the names of all methods in BanchoWriters
+func Writers() []string {
+	writers := reflect.TypeFor[BanchoWriters]()
+	names := make([]string, writers.NumMethod())
+	for i := range names {
+		names[i] = writers.Method(i).Name
+	}
+	return names
+}
+
+// Constructor is implemented by clients that can create a new instance of their
+// version, without any of the overrides that were applied to the original one
+type Constructor interface {
+	New() BanchoIO
+}
+
+// NoopWriters returns the names of every writer that doesn't write anything for the client.
+// Every writer is called twice, once with empty values and once with sample values, since
+// some writers skip only specific inputs, e.g. messages to channels other than #osu.
+//
+// Writers are called on a new instance if the client implements Constructor, with the
+// fallback strategy set to FallbackDrop, so that emulated packets don't count as supported.
+// Other clients are used as they are.
+func NoopWriters(client BanchoIO) []string {
+	if constructor, ok := client.(Constructor); ok {
+		client = constructor.New()
+		if configurable, ok := client.(ConfigurableIO); ok {
+			configurable.OverrideFallback(NewFallbackPolicy(FallbackDrop))
+		}
+	}
+
+	noops := make([]string, 0)
+	value := reflect.ValueOf(client)
+
+	for _, name := range Writers() {
+		method := value.MethodByName(name)
+		if !writesData(method, emptyValue) && !writesData(method, sampleValue) {
+			noops = append(noops, name)
+		}
+	}
+
+	return noops
+}
+
+// writesData calls a writer with the provided arguments, and checks if anything was written
+func writesData(method reflect.Value, argument func(reflect.Type) reflect.Value) bool {
+	stream := &bytes.Buffer{}
+	args := []reflect.Value{reflect.ValueOf(io.Writer(stream))}
+
+	for i := 1; i < method.Type().NumIn(); i++ {
+		args = append(args, argument(method.Type().In(i)))
+	}
+
+	method.Call(args)
+	return stream.Len() > 0
+}
+
+// emptyValue creates the zero value of the type, but with every pointer
+// allocated, so that writers can't dereference a nil pointer
+func emptyValue(t reflect.Type) reflect.Value {
+	value := reflect.New(t).Elem()
+	allocateValue(value, 0)
+	return value
+}
+
+func allocateValue(value reflect.Value, depth int) {
+	if depth > 4 {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		value.Set(reflect.New(value.Type().Elem()))
+		allocateValue(value.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Field(i).CanSet() {
+				allocateValue(value.Field(i), depth+1)
+			}
+		}
+	}
+}
+
+// sampleValue creates a value of the type, where every field is filled in
+func sampleValue(t reflect.Type) reflect.Value {
+	value := reflect.New(t).Elem()
+	fillValue(value, 0)
+	return value
+}
+
+func fillValue(value reflect.Value, depth int) {
+	if depth > 4 {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString("#osu")
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(1)
+	case reflect.Pointer:
+		value.Set(reflect.New(value.Type().Elem()))
+		fillValue(value.Elem(), depth+1)
+	case reflect.Slice:
+		value.Set(reflect.MakeSlice(value.Type(), 1, 1))
+		fillValue(value.Index(0), depth+1)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Field(i).CanSet() {
+				fillValue(value.Field(i), depth+1)
+			}
+		}
+	}
+}
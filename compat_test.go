@@ -0,0 +1,61 @@
+package chio_test
+
+import (
+	"slices"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+func TestCompatibilitySupports(t *testing.T) {
+	compat, ok := chio.GetCompatibility(282)
+	if !ok {
+		t.Fatal("b282 is not registered")
+	}
+
+	packet, ok := compat.Supports(chio.BanchoHandleOsuUpdate)
+	if !ok || packet.WireId != 12 || !packet.Translated() {
+		t.Fatalf("expected BanchoHandleOsuUpdate to be translated to 12, got %+v", packet)
+	}
+	if packet, ok := compat.Supports(chio.BanchoSendMessage); !ok || packet.Translated() {
+		t.Fatalf("expected BanchoSendMessage to keep its id, got %+v", packet)
+	}
+	if _, ok := compat.Supports(chio.BanchoAnnounce); ok {
+		t.Fatal("expected BanchoAnnounce to be unsupported")
+	}
+	if _, ok := chio.GetCompatibility(283); ok {
+		t.Fatal("expected no compatibility for an unregistered version")
+	}
+}
+
+func TestNoopWriters(t *testing.T) {
+	noops := chio.NoopWriters(clients.NewB282())
+	for _, writer := range []string{"WriteAnnouncement", "WriteRestart", "WriteChannelJoinSuccess"} {
+		if !slices.Contains(noops, writer) {
+			t.Errorf("expected %s to be a no-op for b282", writer)
+		}
+	}
+	for _, writer := range []string{"WriteMessage", "WriteUserStats", "WriteUserPresence", "WriteSpectateFrames"} {
+		if slices.Contains(noops, writer) {
+			t.Errorf("expected %s to write data for b282", writer)
+		}
+	}
+
+	noops = chio.NoopWriters(clients.NewB323())
+	if slices.Contains(noops, "WriteAnnouncement") || !slices.Contains(noops, "WriteMatchChangePassword") {
+		t.Errorf("expected b323 to support announcements, but not match passwords: %v", noops)
+	}
+}
+
+func TestNoopWritersIgnoreFallback(t *testing.T) {
+	client := clients.NewB282()
+	client.OverrideFallback(chio.NewFallbackPolicy(chio.FallbackEmulate))
+
+	if !slices.Equal(chio.NoopWriters(client), chio.NoopWriters(clients.NewB282())) {
+		t.Fatal("the fallback strategy of the client changed the no-op writers")
+	}
+	if client.Fallback.Mode(chio.BanchoRestart) != chio.FallbackEmulate {
+		t.Fatal("the fallback strategy of the client was changed")
+	}
+}
@@ -0,0 +1,139 @@
+package chio
+
+import (
+	"compress/gzip"
+	"fmt"
+	"sync"
+)
+
+// CompressionMode decides whether the payload of a packet is gzip compressed
+type CompressionMode uint8
+
+const (
+	// CompressAlways compresses every non-empty payload,
+	// which is what every client in this package expects
+	CompressAlways CompressionMode = iota
+	// CompressThreshold only compresses payloads that
+	// are at least as large as the threshold of the policy
+	CompressThreshold
+	// CompressNone never compresses payloads
+	CompressNone
+)
+
+func (mode CompressionMode) String() string {
+	switch mode {
+	case CompressAlways:
+		return "always"
+	case CompressThreshold:
+		return "threshold"
+	case CompressNone:
+		return "none"
+	}
+	return fmt.Sprintf("CompressionMode(%d)", uint8(mode))
+}
+
+// DefaultCompressionThreshold is the smallest payload that is compressed in CompressThreshold mode.
+// Below it, the gzip header & trailer alone are larger than the payload.
+const DefaultCompressionThreshold = 20
+
+// CompressionStrategy decides which packets a client compresses, and how
+type CompressionStrategy interface {
+	// Mode returns how the payloads of a packet are compressed
+	Mode(packetId uint16) CompressionMode
+
+	// Compresses reports whether a payload of the given size is compressed
+	Compresses(packetId uint16, size int) bool
+
+	// Level returns the gzip compression level, e.g. gzip.BestSpeed
+	Level() int
+}
+
+// CompressionPolicy is a CompressionStrategy with a default mode, that can be overridden per packet.
+//
+// Clients without CompressAlways accept both compressed and uncompressed payloads, which
+// are told apart by the gzip header. Uncompressed payloads that are at least 20 bytes long and
+// happen to start with that header would be misread, so the threshold should not exceed
+// DefaultCompressionThreshold for packets where that can happen.
+type CompressionPolicy struct {
+	defaultMode CompressionMode
+	threshold   int
+	level       int
+	modes       map[uint16]CompressionMode
+	mu          sync.RWMutex
+}
+
+func NewCompressionPolicy(defaultMode CompressionMode) *CompressionPolicy {
+	return &CompressionPolicy{
+		defaultMode: defaultMode,
+		threshold:   DefaultCompressionThreshold,
+		level:       gzip.DefaultCompression,
+		modes:       make(map[uint16]CompressionMode),
+	}
+}
+
+// DefaultCompression compresses every packet with the default gzip level
+var DefaultCompression CompressionStrategy = NewCompressionPolicy(CompressAlways)
+
+// Set overrides the mode for a packet
+func (policy *CompressionPolicy) Set(packetId uint16, mode CompressionMode) *CompressionPolicy {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	policy.modes[packetId] = mode
+	return policy
+}
+
+// WithLevel sets the gzip compression level
+func (policy *CompressionPolicy) WithLevel(level int) *CompressionPolicy {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	policy.level = level
+	return policy
+}
+
+// WithThreshold sets the smallest payload that is compressed in CompressThreshold mode
+func (policy *CompressionPolicy) WithThreshold(threshold int) *CompressionPolicy {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	policy.threshold = threshold
+	return policy
+}
+
+// Default returns the mode of packets without an override
+func (policy *CompressionPolicy) Default() CompressionMode {
+	policy.mu.RLock()
+	defer policy.mu.RUnlock()
+	return policy.defaultMode
+}
+
+// Threshold returns the smallest payload that is compressed in CompressThreshold mode
+func (policy *CompressionPolicy) Threshold() int {
+	policy.mu.RLock()
+	defer policy.mu.RUnlock()
+	return policy.threshold
+}
+
+func (policy *CompressionPolicy) Mode(packetId uint16) CompressionMode {
+	policy.mu.RLock()
+	defer policy.mu.RUnlock()
+
+	if mode, ok := policy.modes[packetId]; ok {
+		return mode
+	}
+	return policy.defaultMode
+}
+
+func (policy *CompressionPolicy) Compresses(packetId uint16, size int) bool {
+	switch policy.Mode(packetId) {
+	case CompressAlways:
+		return size > 0
+	case CompressThreshold:
+		return size > 0 && size >= policy.Threshold()
+	}
+	return false
+}
+
+func (policy *CompressionPolicy) Level() int {
+	policy.mu.RLock()
+	defer policy.mu.RUnlock()
+	return policy.level
+}
@@ -0,0 +1,126 @@
+package chio
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FallbackMode decides what a client does with a packet that it doesn't support
+type FallbackMode uint8
+
+const (
+	// FallbackDrop silently skips the packet
+	FallbackDrop FallbackMode = iota
+	// FallbackEmulate replaces the packet with something the client
+	// understands, e.g. a chat message. Packets that can't be emulated
+	// will be dropped.
+	FallbackEmulate
+	// FallbackError returns ErrNotImplemented from the writer
+	FallbackError
+)
+
+func (mode FallbackMode) String() string {
+	switch mode {
+	case FallbackDrop:
+		return "drop"
+	case FallbackEmulate:
+		return "emulate"
+	case FallbackError:
+		return "error"
+	}
+	return fmt.Sprintf("FallbackMode(%d)", uint8(mode))
+}
+
+// FallbackStrategy decides how packets that are not supported by a client are handled
+type FallbackStrategy interface {
+	// Mode returns how the packet should be handled. For BanchoSendMessage,
+	// it applies to messages that can't be delivered to their target, e.g.
+	// private messages in versions without them.
+	Mode(packetId uint16) FallbackMode
+
+	// BotName returns the sender of messages that are used for emulation
+	BotName() string
+}
+
+// FallbackPolicy is a FallbackStrategy with a default mode, that can be overridden per packet.
+// It is safe for concurrent use, so that it can be shared between clients.
+type FallbackPolicy struct {
+	defaultMode FallbackMode
+	bot         string
+	modes       map[uint16]FallbackMode
+	mu          sync.RWMutex
+}
+
+func NewFallbackPolicy(defaultMode FallbackMode) *FallbackPolicy {
+	return &FallbackPolicy{
+		defaultMode: defaultMode,
+		bot:         "BanchoBot",
+		modes:       make(map[uint16]FallbackMode),
+	}
+}
+
+// DefaultFallback drops every unsupported packet, except for restarts, which are announced.
+// It is shared by every client that doesn't override its fallback strategy.
+var DefaultFallback FallbackStrategy = NewFallbackPolicy(FallbackDrop).Set(BanchoRestart, FallbackEmulate)
+
+// Set overrides the mode for a packet
+func (policy *FallbackPolicy) Set(packetId uint16, mode FallbackMode) *FallbackPolicy {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	policy.modes[packetId] = mode
+	return policy
+}
+
+// WithDefault sets the mode for packets that were not overridden with Set
+func (policy *FallbackPolicy) WithDefault(mode FallbackMode) *FallbackPolicy {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	policy.defaultMode = mode
+	return policy
+}
+
+// WithBot sets the sender of messages that are used for emulation
+func (policy *FallbackPolicy) WithBot(name string) *FallbackPolicy {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	policy.bot = name
+	return policy
+}
+
+// Default returns the mode for packets that were not overridden with Set
+func (policy *FallbackPolicy) Default() FallbackMode {
+	policy.mu.RLock()
+	defer policy.mu.RUnlock()
+	return policy.defaultMode
+}
+
+func (policy *FallbackPolicy) Mode(packetId uint16) FallbackMode {
+	policy.mu.RLock()
+	defer policy.mu.RUnlock()
+
+	if mode, ok := policy.modes[packetId]; ok {
+		return mode
+	}
+	return policy.defaultMode
+}
+
+func (policy *FallbackPolicy) BotName() string {
+	policy.mu.RLock()
+	defer policy.mu.RUnlock()
+	return policy.bot
+}
+
+// InlineTarget moves a message into #osu, with its original target as a prefix, e.g.
+// "(#lobby) hello" or "(PM to peppy) hello". It is used for versions that can't
+// display the target of a message.
+func InlineTarget(message Message) Message {
+	prefix := "(PM to " + message.Target + ") "
+	if strings.HasPrefix(message.Target, "#") {
+		prefix = "(" + message.Target + ") "
+	}
+
+	message.Content = prefix + message.Content
+	message.Target = "#osu"
+	return message
+}
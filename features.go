@@ -0,0 +1,177 @@
+package chio
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Feature is a capability of a client version, that
+// may span multiple packets or changes in their layout
+type Feature uint8
+
+const (
+	// Spectating other players and receiving their replay frames
+	FeatureSpectating Feature = iota
+	// Replay frame bundles contain the current score of the player
+	FeatureSpectatorScoreFrames
+	// Score frames contain the time at which they were recorded
+	FeatureScoreFrameTime
+	// Spectators are notified about other spectators of the same player
+	FeatureFellowSpectators
+	// Messages & presences of users that are connected through IRC
+	FeatureIrcUsers
+	// Announcements, which are displayed as a notification
+	FeatureAnnouncements
+	// Flashing the window to get the attention of the player
+	FeatureGetAttention
+	// Private messages between two players
+	FeaturePrivateMessages
+	// Messages contain the channel that they were sent to
+	FeatureMessageTargets
+	// Joining & leaving channels other than #osu
+	FeatureChannels
+	// The multiplayer lobby, as well as creating & joining matches
+	FeatureMultiplayer
+	// Starting matches and receiving score updates of other players
+	FeatureMatchGameplay
+	// Changing the beatmap of a match
+	FeatureMatchChangeBeatmap
+	// Assigning players of a match to teams
+	FeatureSlotTeams
+	// Players of a match choosing their own mods
+	FeatureFreemod
+	// Friends lists
+	FeatureFriends
+	// Status updates may leave out the stats of a user
+	FeatureStatusUpdates
+)
+
+var featureNames = map[Feature]string{
+	FeatureSpectating:           "Spectating",
+	FeatureSpectatorScoreFrames: "SpectatorScoreFrames",
+	FeatureScoreFrameTime:       "ScoreFrameTime",
+	FeatureFellowSpectators:     "FellowSpectators",
+	FeatureIrcUsers:             "IrcUsers",
+	FeatureAnnouncements:        "Announcements",
+	FeatureGetAttention:         "GetAttention",
+	FeaturePrivateMessages:      "PrivateMessages",
+	FeatureMessageTargets:       "MessageTargets",
+	FeatureChannels:             "Channels",
+	FeatureMultiplayer:          "Multiplayer",
+	FeatureMatchGameplay:        "MatchGameplay",
+	FeatureMatchChangeBeatmap:   "MatchChangeBeatmap",
+	FeatureSlotTeams:            "SlotTeams",
+	FeatureFreemod:              "Freemod",
+	FeatureFriends:              "Friends",
+	FeatureStatusUpdates:        "StatusUpdates",
+}
+
+// featurePackets contains the packets that are required for a feature.
+// Features that are not listed here depend on the layout of packets,
+// and have to be declared by the client versions themselves.
+var featurePackets = map[Feature][]uint16{
+	FeatureSpectating:         {OsuStartSpectating, OsuStopSpectating, OsuSpectateFrames, BanchoSpectateFrames},
+	FeatureFellowSpectators:   {BanchoFellowSpectatorJoined, BanchoFellowSpectatorLeft},
+	FeatureIrcUsers:           {BanchoHandleIrcJoin, BanchoHandleIrcQuit},
+	FeatureAnnouncements:      {BanchoAnnounce},
+	FeatureGetAttention:       {BanchoGetAttention},
+	FeaturePrivateMessages:    {OsuSendIrcMessagePrivate},
+	FeatureChannels:           {OsuChannelJoin, OsuChannelLeave, BanchoChannelJoinSuccess, BanchoChannelRevoked},
+	FeatureMultiplayer:        {OsuLobbyJoin, OsuLobbyPart, OsuMatchCreate, OsuMatchJoin, OsuMatchPart, BanchoMatchUpdate},
+	FeatureMatchGameplay:      {OsuMatchStart, BanchoMatchStart, OsuMatchScoreUpdate, BanchoMatchScoreUpdate},
+	FeatureMatchChangeBeatmap: {OsuMatchChangeBeatmap},
+	FeatureSlotTeams:          {OsuMatchChangeTeam},
+	FeatureFriends:            {BanchoFriendsList, OsuFriendsAdd, OsuFriendsRemove},
+}
+
+func (feature Feature) String() string {
+	if name, ok := featureNames[feature]; ok {
+		return name
+	}
+	return fmt.Sprintf("Feature(%d)", uint8(feature))
+}
+
+// RequiredPackets returns the packets that a client has to implement for the
+// feature, or false if the feature does not depend on specific packets
+func (feature Feature) RequiredPackets() ([]uint16, bool) {
+	packets, ok := featurePackets[feature]
+	return packets, ok
+}
+
+// Features returns every known feature
+func Features() []Feature {
+	features := make([]Feature, len(featureNames))
+	for i := range features {
+		features[i] = Feature(i)
+	}
+	return features
+}
+
+// FeatureSet is a set of features
+type FeatureSet uint64
+
+func NewFeatureSet(features ...Feature) FeatureSet {
+	return FeatureSet(0).With(features...)
+}
+
+// With returns a copy of the set, that also contains the provided features
+func (set FeatureSet) With(features ...Feature) FeatureSet {
+	for _, feature := range features {
+		set |= 1 << feature
+	}
+	return set
+}
+
+// Has checks if the feature is part of the set
+func (set FeatureSet) Has(feature Feature) bool {
+	return set&(1<<feature) != 0
+}
+
+// Features returns every feature of the set in ascending order
+func (set FeatureSet) Features() []Feature {
+	features := make([]Feature, 0, bits.OnesCount64(uint64(set)))
+	for _, feature := range Features() {
+		if set.Has(feature) {
+			features = append(features, feature)
+		}
+	}
+	return features
+}
+
+// Supports checks if a client has a feature
+func Supports(client BanchoIO, feature Feature) bool {
+	return ClientFeatures(client).Has(feature)
+}
+
+// ClientFeatures returns every feature of a client. Clients that don't implement
+// FeatureIO only have the features that are implied by the packets they implement.
+func ClientFeatures(client BanchoIO) FeatureSet {
+	if io, ok := client.(FeatureIO); ok {
+		return io.Features()
+	}
+	return impliedFeatures(client.ImplementsPacket)
+}
+
+// PacketFeatures returns the features that are implied by the packets of a table
+func PacketFeatures(table *PacketTable) FeatureSet {
+	return impliedFeatures(table.Contains)
+}
+
+func impliedFeatures(contains func(packetId uint16) bool) FeatureSet {
+	set := FeatureSet(0)
+
+	for feature, packets := range featurePackets {
+		supported := true
+		for _, packetId := range packets {
+			if !contains(packetId) {
+				supported = false
+				break
+			}
+		}
+		if supported {
+			set = set.With(feature)
+		}
+	}
+
+	return set
+}
@@ -0,0 +1,64 @@
+package chio_test
+
+import (
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+// legacyClient only implements BanchoIO, like implementations that predate FeatureIO
+type legacyClient struct {
+	chio.BanchoIO
+}
+
+func TestSupportsWithoutFeatureIO(t *testing.T) {
+	client := legacyClient{clients.NewB294()}
+	if _, ok := chio.BanchoIO(client).(chio.FeatureIO); ok {
+		t.Fatal("expected the legacy client to not implement FeatureIO")
+	}
+
+	if !chio.Supports(client, chio.FeatureSpectating) {
+		t.Error("expected features of the packets to be supported")
+	}
+	if chio.Supports(client, chio.FeatureSpectatorScoreFrames) {
+		t.Error("expected layout features to be unknown")
+	}
+}
+
+func TestVersionFeatures(t *testing.T) {
+	// Features that are added by every version, on top of the ones before it
+	added := []struct {
+		version  int
+		features []chio.Feature
+	}{
+		{282, []chio.Feature{chio.FeatureSpectating, chio.FeatureIrcUsers}},
+		{290, nil},
+		{291, []chio.Feature{chio.FeatureAnnouncements, chio.FeatureGetAttention}},
+		{294, []chio.Feature{chio.FeatureSpectatorScoreFrames, chio.FeaturePrivateMessages}},
+		{296, []chio.Feature{chio.FeatureScoreFrameTime}},
+		{298, []chio.Feature{chio.FeatureFellowSpectators, chio.FeatureMultiplayer}},
+		{312, []chio.Feature{chio.FeatureMatchGameplay}},
+		{320, []chio.Feature{chio.FeatureMessageTargets}},
+		{323, []chio.Feature{chio.FeatureMatchChangeBeatmap, chio.FeatureStatusUpdates}},
+	}
+
+	expected := chio.NewFeatureSet()
+	for _, version := range added {
+		expected = expected.With(version.features...)
+
+		client, ok := chio.DefaultRegistry.Get(version.version)
+		if !ok {
+			t.Fatalf("b%d is not registered", version.version)
+		}
+		if features := chio.ClientFeatures(client); features != expected {
+			t.Errorf("b%d: expected %v, got %v", version.version, expected.Features(), features.Features())
+		}
+
+		for _, feature := range chio.Features() {
+			if chio.Supports(client, feature) != expected.Has(feature) {
+				t.Errorf("b%d: expected Supports(%s) to be %v", version.version, feature, expected.Has(feature))
+			}
+		}
+	}
+}
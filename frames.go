@@ -0,0 +1,73 @@
+package chio
+
+import "io"
+
+// ConvertFrameBundle converts a bundle that was read from the source client,
+// so that it can be written to the target client. The bundle is copied, and
+// information that the target can't represent is dropped or approximated:
+//
+//   - The K1 & K2 keys are merged into the left & right mouse buttons, and smoke is dropped
+//   - Bundles of players that are spectating someone else are sent as regular
+//     gameplay, and the extra value is dropped
+//   - Without FeatureSpectatorScoreFrames, the score frame is dropped
+//   - With FeatureScoreFrameTime, score frames of sources without it get
+//     the time of the last replay frame in the bundle
+//
+// None of the supported versions send more than the two mouse buttons, or know about
+// spectators that watch someone else, so the first two conversions always apply.
+// Bundles that were read from a supported version are not affected by them.
+func ConvertFrameBundle(bundle ReplayFrameBundle, source BanchoIO, target BanchoIO) ReplayFrameBundle {
+	converted := ReplayFrameBundle{
+		Action: bundle.Action,
+		Frames: make([]*ReplayFrame, 0, len(bundle.Frames)),
+	}
+
+	for _, frame := range bundle.Frames {
+		if frame == nil {
+			continue
+		}
+		copied := *frame
+		copied.ButtonState = ConvertButtonState(copied.ButtonState)
+		converted.Frames = append(converted.Frames, &copied)
+	}
+
+	if converted.Action == ReplayActionWatchingOther {
+		converted.Action = ReplayActionStandard
+	}
+
+	if bundle.Frame == nil || !Supports(target, FeatureSpectatorScoreFrames) {
+		return converted
+	}
+
+	frame := *bundle.Frame
+	converted.Frame = &frame
+
+	synthesizeTime := Supports(target, FeatureScoreFrameTime) &&
+		!Supports(source, FeatureScoreFrameTime) &&
+		len(converted.Frames) > 0
+
+	if synthesizeTime {
+		converted.Frame.Time = converted.Frames[len(converted.Frames)-1].Time
+	}
+
+	return converted
+}
+
+// ConvertButtonState merges the K1 & K2 keys into the left & right mouse buttons, and drops smoke
+func ConvertButtonState(state uint8) uint8 {
+	converted := state & (ButtonStateLeft1 | ButtonStateRight1)
+
+	if state&ButtonStateLeft2 != 0 {
+		converted |= ButtonStateLeft1
+	}
+	if state&ButtonStateRight2 != 0 {
+		converted |= ButtonStateRight1
+	}
+
+	return converted
+}
+
+// RelayFrames converts a bundle that was read from the source client, and writes it to the target client
+func RelayFrames(stream io.Writer, bundle ReplayFrameBundle, source BanchoIO, target BanchoIO) error {
+	return target.WriteSpectateFrames(stream, ConvertFrameBundle(bundle, source, target))
+}
@@ -1,5 +1,3 @@
 module github.com/Lekuruu/chio-go
 
 go 1.22.7
-
-require github.com/bnch/uleb128 v0.0.0-20251110171552-8b02f5c482e8
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// The "binary" variants reproduce the previous encoding/binary based codec,
+// so that the gain of the current implementation stays measurable.
+
+func BenchmarkWriteUint32(b *testing.B) {
+	b.Run("binary", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := new(bytes.Buffer)
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			binary.Write(buf, binary.LittleEndian, uint32(i))
+		}
+	})
+	b.Run("internal", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := new(bytes.Buffer)
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			WriteUint32(buf, uint32(i))
+		}
+	})
+}
+
+func BenchmarkWriteString(b *testing.B) {
+	b.Run("binary", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := new(bytes.Buffer)
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			binary.Write(buf, binary.LittleEndian, uint8(0x0b))
+			buf.Write(AppendUleb128(nil, 13))
+			buf.Write([]byte("Hello, World!"))
+		}
+	})
+	b.Run("internal", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := new(bytes.Buffer)
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			WriteString(buf, "Hello, World!")
+		}
+	})
+}
+
+func BenchmarkReadUint32(b *testing.B) {
+	data := []byte{0x01, 0x02, 0x03, 0x04}
+
+	b.Run("binary", func(b *testing.B) {
+		b.ReportAllocs()
+		r := bytes.NewReader(data)
+		for i := 0; i < b.N; i++ {
+			r.Reset(data)
+			var v uint32
+			binary.Read(r, binary.LittleEndian, &v)
+		}
+	})
+	b.Run("internal", func(b *testing.B) {
+		b.ReportAllocs()
+		r := bytes.NewReader(data)
+		for i := 0; i < b.N; i++ {
+			r.Reset(data)
+			ReadUint32(r)
+		}
+	})
+}
+
+func BenchmarkCompressData(b *testing.B) {
+	data := bytes.Repeat([]byte("chio"), 64)
+
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			zb := new(bytes.Buffer)
+			zw := gzip.NewWriter(zb)
+			zw.Write(data)
+			zw.Close()
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			CompressTo(io.Discard, data)
+		}
+	})
+}
+
+func BenchmarkDecompressData(b *testing.B) {
+	data := CompressData(bytes.Repeat([]byte("chio"), 64))
+
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			zr, _ := gzip.NewReader(bytes.NewReader(data))
+			io.Copy(io.Discard, zr)
+			zr.Close()
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		dst := new(bytes.Buffer)
+		for i := 0; i < b.N; i++ {
+			dst.Reset()
+			DecompressTo(dst, data)
+		}
+	})
+}
@@ -3,18 +3,87 @@ package internal
 import (
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
+	"sync"
 )
 
+// MinCompressedSize is a lower bound for the size of gzip streams:
+// a 10 byte header, at least 2 bytes of deflate data and an 8 byte trailer
+const MinCompressedSize = 20
+
+// gzipWriterPools holds one pool of compressors for every
+// level, from gzip.HuffmanOnly to gzip.BestCompression
+var gzipWriterPools [gzip.BestCompression - gzip.HuffmanOnly + 1]sync.Pool
+
+var gzipReaderPool = sync.Pool{
+	New: func() any { return new(gzip.Reader) },
+}
+
+// CompressTo writes the gzip compressed data to w, using a pooled compressor
+func CompressTo(w io.Writer, data []byte) error {
+	return CompressLevelTo(w, data, gzip.DefaultCompression)
+}
+
+// CompressLevelTo writes the data to w, compressed with the given gzip level
+func CompressLevelTo(w io.Writer, data []byte, level int) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return fmt.Errorf("invalid compression level %d", level)
+	}
+	pool := &gzipWriterPools[level-gzip.HuffmanOnly]
+
+	zw, ok := pool.Get().(*gzip.Writer)
+	if !ok {
+		zw, _ = gzip.NewWriterLevel(nil, level)
+	}
+	defer pool.Put(zw)
+
+	zw.Reset(w)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
 func CompressData(data []byte) []byte {
 	if len(data) == 0 {
 		return []byte{}
 	}
-	zb := new(bytes.Buffer)
-	zw := gzip.NewWriter(zb)
-	zw.Write(data)
-	zw.Close()
-	return zb.Bytes()
+	zb := GetBuffer()
+	defer PutBuffer(zb)
+	CompressTo(zb, data)
+	return bytes.Clone(zb.Bytes())
+}
+
+// DecompressTo appends the decompressed data to dst, using a pooled decompressor
+func DecompressTo(dst *bytes.Buffer, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	zr := gzipReaderPool.Get().(*gzip.Reader)
+	defer gzipReaderPool.Put(zr)
+
+	if err := zr.Reset(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	// Reading one byte past the limit tells apart payloads that
+	// are exactly at the limit from ones that exceed it
+	n, err := dst.ReadFrom(io.LimitReader(zr, MaxPacketSize+1))
+	if err != nil {
+		return err
+	}
+	if n > MaxPacketSize {
+		return fmt.Errorf("decompressed payload: %w", ErrTooLarge)
+	}
+	return zr.Close()
+}
+
+// IsCompressed reports whether the data starts with a gzip header, and is long enough to be a gzip stream
+func IsCompressed(data []byte) bool {
+	return len(data) >= MinCompressedSize && data[0] == 0x1f && data[1] == 0x8b
 }
 
 func DecompressData(data []byte) ([]byte, error) {
@@ -22,11 +91,8 @@ func DecompressData(data []byte) ([]byte, error) {
 		return []byte{}, nil
 	}
 	dst := bytes.NewBuffer([]byte{})
-	zr, err := gzip.NewReader(bytes.NewReader(data))
-	if err != nil {
+	if err := DecompressTo(dst, data); err != nil {
 		return nil, err
 	}
-	io.Copy(dst, zr)
-	zr.Close()
 	return dst.Bytes(), nil
 }
@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// FrameHeaderSize is the size of the packet id & length that precede every frame
+const FrameHeaderSize = 2 + 4
+
+// FrameBuffer reassembles packet frames from data that may be split up
+// arbitrarily, e.g. by the reads & writes of a network stream
+type FrameBuffer struct {
+	pending bytes.Buffer
+}
+
+// Write appends data to the pending frames
+func (buffer *FrameBuffer) Write(p []byte) (int, error) {
+	return buffer.pending.Write(p)
+}
+
+// Next returns the next complete frame, or nil if there is none yet. The frame is
+// only valid until the next call to Write. Frames larger than MaxPacketSize can't
+// be reassembled, in which case the pending data is discarded and an error returned.
+func (buffer *FrameBuffer) Next() ([]byte, error) {
+	if buffer.pending.Len() < FrameHeaderSize {
+		return nil, nil
+	}
+
+	length := binary.LittleEndian.Uint32(buffer.pending.Bytes()[2:FrameHeaderSize])
+	if length > MaxPacketSize {
+		buffer.pending.Reset()
+		return nil, fmt.Errorf("frame of %d bytes: %w", length, ErrTooLarge)
+	}
+
+	size := FrameHeaderSize + int(length)
+	if buffer.pending.Len() < size {
+		return nil, nil
+	}
+	return buffer.pending.Next(size), nil
+}
+
+// Len returns the amount of bytes that don't belong to a complete frame yet
+func (buffer *FrameBuffer) Len() int {
+	return buffer.pending.Len()
+}
+
+// Reset discards the pending data
+func (buffer *FrameBuffer) Reset() {
+	buffer.pending.Reset()
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzReadString(f *testing.F) {
+	f.Add([]byte{0x00})
+	f.Add([]byte{0x0b, 0x05, 'h', 'e', 'l', 'l', 'o'})
+	f.Add([]byte{0x0b, 0xff, 0xff, 0xff, 0xff, 0x0f})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		value, err := ReadString(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		// Lengths may be encoded with redundant bytes, so only
+		// the value has to survive encoding it again
+		buffer := &bytes.Buffer{}
+		WriteString(buffer, value)
+		decoded, err := ReadString(buffer)
+		if err != nil || decoded != value {
+			t.Fatalf("%q was decoded as %q after encoding it again: %v", value, decoded, err)
+		}
+	})
+}
+
+func FuzzReadLists(f *testing.F) {
+	f.Add(uint8(8), []byte{0x02, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00})
+	f.Add(uint8(16), []byte{0xff, 0xff, 0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, size uint8, data []byte) {
+		ReadIntList16(bytes.NewReader(data))
+		ReadIntList32(bytes.NewReader(data))
+		ReadBoolList(bytes.NewReader(data), int(size))
+	})
+}
+
+func FuzzDecompressData(f *testing.F) {
+	f.Add(CompressData([]byte("Hello, World!")))
+	f.Add(CompressData(make([]byte, 1024*1024)))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		DecompressData(data)
+	})
+}
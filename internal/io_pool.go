@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufferSize is the largest buffer capacity that will be returned
+// to the pool, so that a single huge packet doesn't pin its memory forever.
+const maxPooledBufferSize = 64 * 1024
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// GetBuffer returns an empty buffer from the pool
+func GetBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// PutBuffer resets the buffer and returns it to the pool.
+// The buffer and any slices obtained from it must not be used afterwards.
+func PutBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
@@ -3,14 +3,69 @@ package internal
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
+	"math"
+)
 
-	"github.com/bnch/uleb128"
+// Limits for values that are read from untrusted input
+const (
+	// MaxPacketSize is the largest packet payload that will be read, before and after decompression
+	MaxPacketSize = 16 * 1024 * 1024
+	// MaxStringLength is the largest string that will be read
+	MaxStringLength = 1024 * 1024
+	// MaxBoolListSize is the amount of booleans that fit into a bool list
+	MaxBoolListSize = 8
 )
 
+// listCapacity is the capacity that lists are preallocated with,
+// so that a bogus length can't cause a huge allocation up front
+const listCapacity = 256
+
+// ErrTooLarge is returned when a length exceeds one of the limits
+var ErrTooLarge = errors.New("value exceeds size limit")
+
+// remaining returns the amount of unread bytes, if r is able to tell
+func remaining(r io.Reader) (int, bool) {
+	if lr, ok := r.(interface{ Len() int }); ok {
+		return lr.Len(), true
+	}
+	return 0, false
+}
+
+// readBytes fills buf from r, returning io.ErrUnexpectedEOF on short reads.
+// Readers implementing io.ByteReader, such as the *bytes.Reader used for
+// packet payloads, are consumed byte by byte, which keeps buf on the stack.
+func readBytes(r io.Reader, buf []byte) error {
+	if br, ok := r.(io.ByteReader); ok {
+		for i := range buf {
+			b, err := br.ReadByte()
+			if err != nil {
+				if i > 0 && err == io.EOF {
+					return io.ErrUnexpectedEOF
+				}
+				return err
+			}
+			buf[i] = b
+		}
+		return nil
+	}
+
+	var scratch [8]byte
+	n := len(buf)
+	if _, err := io.ReadFull(r, scratch[:n]); err != nil {
+		return err
+	}
+	copy(buf, scratch[:n])
+	return nil
+}
+
 func ReadUint64(r io.Reader) (v uint64, err error) {
-	err = binary.Read(r, binary.LittleEndian, &v)
-	return v, err
+	var buf [8]byte
+	if err = readBytes(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
 }
 
 func ReadInt64(r io.Reader) (v int64, err error) {
@@ -19,8 +74,11 @@ func ReadInt64(r io.Reader) (v int64, err error) {
 }
 
 func ReadUint32(r io.Reader) (v uint32, err error) {
-	err = binary.Read(r, binary.LittleEndian, &v)
-	return v, err
+	var buf [4]byte
+	if err = readBytes(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf[:]), nil
 }
 
 func ReadInt32(r io.Reader) (v int32, err error) {
@@ -29,8 +87,11 @@ func ReadInt32(r io.Reader) (v int32, err error) {
 }
 
 func ReadUint16(r io.Reader) (v uint16, err error) {
-	err = binary.Read(r, binary.LittleEndian, &v)
-	return v, err
+	var buf [2]byte
+	if err = readBytes(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(buf[:]), nil
 }
 
 func ReadInt16(r io.Reader) (v int16, err error) {
@@ -39,8 +100,11 @@ func ReadInt16(r io.Reader) (v int16, err error) {
 }
 
 func ReadUint8(r io.Reader) (v uint8, err error) {
-	err = binary.Read(r, binary.LittleEndian, &v)
-	return v, err
+	var buf [1]byte
+	if err = readBytes(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return buf[0], nil
 }
 
 func ReadInt8(r io.Reader) (v int8, err error) {
@@ -49,18 +113,18 @@ func ReadInt8(r io.Reader) (v int8, err error) {
 }
 
 func ReadBoolean(r io.Reader) (v bool, err error) {
-	err = binary.Read(r, binary.LittleEndian, &v)
-	return v, err
+	uv, err := ReadUint8(r)
+	return uv != 0, err
 }
 
 func ReadFloat32(r io.Reader) (v float32, err error) {
-	err = binary.Read(r, binary.LittleEndian, &v)
-	return v, err
+	uv, err := ReadUint32(r)
+	return math.Float32frombits(uv), err
 }
 
 func ReadFloat64(r io.Reader) (v float64, err error) {
-	err = binary.Read(r, binary.LittleEndian, &v)
-	return v, err
+	uv, err := ReadUint64(r)
+	return math.Float64frombits(uv), err
 }
 
 func ReadIntList16(r io.Reader) (v []int32, err error) {
@@ -69,12 +133,17 @@ func ReadIntList16(r io.Reader) (v []int32, err error) {
 		return nil, err
 	}
 
-	v = make([]int32, l)
+	if n, ok := remaining(r); ok && int(l) > n/4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	v = make([]int32, 0, min(int(l), listCapacity))
 	for i := uint16(0); i < l; i++ {
-		v[i], err = ReadInt32(r)
+		value, err := ReadInt32(r)
 		if err != nil {
 			return nil, err
 		}
+		v = append(v, value)
 	}
 
 	return v, nil
@@ -86,18 +155,27 @@ func ReadIntList32(r io.Reader) (v []int32, err error) {
 		return nil, err
 	}
 
-	v = make([]int32, l)
+	if n, ok := remaining(r); ok && int(l) > n/4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	v = make([]int32, 0, min(int(l), listCapacity))
 	for i := uint32(0); i < l; i++ {
-		v[i], err = ReadInt32(r)
+		value, err := ReadInt32(r)
 		if err != nil {
 			return nil, err
 		}
+		v = append(v, value)
 	}
 
 	return v, nil
 }
 
 func ReadBoolList(r io.Reader, size int) ([]bool, error) {
+	if size < 0 || size > MaxBoolListSize {
+		return nil, fmt.Errorf("bool list size must be between 0 and %d, got %d", MaxBoolListSize, size)
+	}
+
 	input, err := ReadUint8(r)
 	if err != nil {
 		return nil, err
@@ -110,9 +188,30 @@ func ReadBoolList(r io.Reader, size int) ([]bool, error) {
 	return bools, nil
 }
 
+// ReadUleb128 reads an unsigned LEB128 encoded integer, as used for string lengths
+func ReadUleb128(r io.Reader) (v int, err error) {
+	var shift uint
+
+	for {
+		b, err := ReadUint8(r)
+		if err != nil {
+			return 0, err
+		}
+
+		v |= int(b&0x7F) << shift
+		if b&0x80 == 0 {
+			return v, nil
+		}
+
+		shift += 7
+		if shift >= 63 {
+			return 0, errors.New("uleb128 value overflows int")
+		}
+	}
+}
+
 func ReadString(r io.Reader) (v string, err error) {
-	var b uint8
-	err = binary.Read(r, binary.LittleEndian, &b)
+	b, err := ReadUint8(r)
 	if err != nil {
 		return "", err
 	}
@@ -125,9 +224,19 @@ func ReadString(r io.Reader) (v string, err error) {
 		return "", errors.New("invalid string type")
 	}
 
-	l := uleb128.UnmarshalReader(r)
+	l, err := ReadUleb128(r)
+	if err != nil {
+		return "", err
+	}
+	if l > MaxStringLength {
+		return "", fmt.Errorf("string length %d: %w", l, ErrTooLarge)
+	}
+	if n, ok := remaining(r); ok && l > n {
+		return "", io.ErrUnexpectedEOF
+	}
+
 	buf := make([]byte, l)
-	_, err = r.Read(buf)
+	_, err = io.ReadFull(r, buf)
 	if err != nil {
 		return "", err
 	}
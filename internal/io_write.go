@@ -4,12 +4,25 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
-
-	"github.com/bnch/uleb128"
+	"math"
 )
 
+// appendWriter is implemented by writers that expose their spare capacity,
+// like *bytes.Buffer. Values can be appended to it without a scratch buffer.
+type appendWriter interface {
+	io.Writer
+	AvailableBuffer() []byte
+}
+
 func WriteUint64(w io.Writer, v uint64) error {
-	return binary.Write(w, binary.LittleEndian, v)
+	if aw, ok := w.(appendWriter); ok {
+		_, err := aw.Write(binary.LittleEndian.AppendUint64(aw.AvailableBuffer(), v))
+		return err
+	}
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
 }
 
 func WriteInt64(w io.Writer, v int64) error {
@@ -17,7 +30,14 @@ func WriteInt64(w io.Writer, v int64) error {
 }
 
 func WriteUint32(w io.Writer, v uint32) error {
-	return binary.Write(w, binary.LittleEndian, v)
+	if aw, ok := w.(appendWriter); ok {
+		_, err := aw.Write(binary.LittleEndian.AppendUint32(aw.AvailableBuffer(), v))
+		return err
+	}
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
 }
 
 func WriteInt32(w io.Writer, v int32) error {
@@ -25,7 +45,14 @@ func WriteInt32(w io.Writer, v int32) error {
 }
 
 func WriteUint16(w io.Writer, v uint16) error {
-	return binary.Write(w, binary.LittleEndian, v)
+	if aw, ok := w.(appendWriter); ok {
+		_, err := aw.Write(binary.LittleEndian.AppendUint16(aw.AvailableBuffer(), v))
+		return err
+	}
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
 }
 
 func WriteInt16(w io.Writer, v int16) error {
@@ -33,7 +60,11 @@ func WriteInt16(w io.Writer, v int16) error {
 }
 
 func WriteUint8(w io.Writer, v uint8) error {
-	return binary.Write(w, binary.LittleEndian, v)
+	if bw, ok := w.(io.ByteWriter); ok {
+		return bw.WriteByte(v)
+	}
+	_, err := w.Write([]byte{v})
+	return err
 }
 
 func WriteInt8(w io.Writer, v int8) error {
@@ -41,15 +72,18 @@ func WriteInt8(w io.Writer, v int8) error {
 }
 
 func WriteBoolean(w io.Writer, v bool) error {
-	return binary.Write(w, binary.LittleEndian, v)
+	if v {
+		return WriteUint8(w, 1)
+	}
+	return WriteUint8(w, 0)
 }
 
 func WriteFloat32(w io.Writer, v float32) error {
-	return binary.Write(w, binary.LittleEndian, v)
+	return WriteUint32(w, math.Float32bits(v))
 }
 
 func WriteFloat64(w io.Writer, v float64) error {
-	return binary.Write(w, binary.LittleEndian, v)
+	return WriteUint64(w, math.Float64bits(v))
 }
 
 func WriteIntList16(w io.Writer, v []int32) error {
@@ -93,17 +127,38 @@ func WriteBoolList(w io.Writer, bools []bool, size int) error {
 	return WriteUint8(w, result)
 }
 
+// AppendUleb128 appends v to buf as an unsigned LEB128 encoded integer
+func AppendUleb128(buf []byte, v int) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+func WriteUleb128(w io.Writer, v int) error {
+	if aw, ok := w.(appendWriter); ok {
+		_, err := aw.Write(AppendUleb128(aw.AvailableBuffer(), v))
+		return err
+	}
+	var buf [binary.MaxVarintLen64]byte
+	_, err := w.Write(AppendUleb128(buf[:0], v))
+	return err
+}
+
 func WriteString(w io.Writer, v string) error {
 	if v == "" {
-		binary.Write(w, binary.LittleEndian, uint8(0x00))
-		return nil
+		return WriteUint8(w, 0x00)
 	}
 
-	if err := binary.Write(w, binary.LittleEndian, uint8(0x0b)); err != nil {
+	if err := WriteUint8(w, 0x0b); err != nil {
 		return err
 	}
 
-	w.Write(uleb128.Marshal(len(v)))
-	w.Write([]byte(v))
-	return nil
+	if err := WriteUleb128(w, len(v)); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, v)
+	return err
 }
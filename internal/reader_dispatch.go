@@ -3,6 +3,7 @@ package internal
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	chio "github.com/Lekuruu/chio-go"
 )
@@ -34,14 +35,23 @@ type (
 		ReadMatchJoin(io.Reader) (*chio.MatchJoin, error)
 	}
 	MatchChangeSlotReader interface {
-		ReadMatchChangeSlot(io.Reader) (int32, error)
+		ReadMatchChangeSlot(io.Reader) (*chio.MatchChangeSlot, error)
 	}
 	MatchLockReader interface {
-		ReadMatchLock(io.Reader) (int32, error)
+		ReadMatchLock(io.Reader) (*chio.MatchLock, error)
 	}
 	ScoreFrameReader interface {
 		ReadScoreFrame(io.Reader) (*chio.ScoreFrame, error)
 	}
+	BanchoMessageReader interface {
+		ReadBanchoMessage(io.Reader) (*chio.Message, error)
+	}
+	UserStatsReader interface {
+		ReadUserStats(io.Reader) (*chio.UserInfo, error)
+	}
+	UserQuitReader interface {
+		ReadUserQuit(io.Reader) (*chio.UserQuit, error)
+	}
 )
 
 // dispatchReader creates a PacketReader that delegates to the client's method.
@@ -109,7 +119,7 @@ func ReaderReadMatchJoin() chio.PacketReader {
 }
 
 func ReaderReadMatchChangeSlot() chio.PacketReader {
-	return dispatchReader("ReadMatchChangeSlot", func(c chio.BanchoIO) (func(io.Reader) (int32, error), bool) {
+	return dispatchReader("ReadMatchChangeSlot", func(c chio.BanchoIO) (func(io.Reader) (*chio.MatchChangeSlot, error), bool) {
 		if h, ok := c.(MatchChangeSlotReader); ok {
 			return h.ReadMatchChangeSlot, true
 		}
@@ -118,7 +128,7 @@ func ReaderReadMatchChangeSlot() chio.PacketReader {
 }
 
 func ReaderReadMatchLock() chio.PacketReader {
-	return dispatchReader("ReadMatchLock", func(c chio.BanchoIO) (func(io.Reader) (int32, error), bool) {
+	return dispatchReader("ReadMatchLock", func(c chio.BanchoIO) (func(io.Reader) (*chio.MatchLock, error), bool) {
 		if h, ok := c.(MatchLockReader); ok {
 			return h.ReadMatchLock, true
 		}
@@ -135,6 +145,33 @@ func ReaderReadScoreFrame() chio.PacketReader {
 	})
 }
 
+func ReaderReadBanchoMessage() chio.PacketReader {
+	return dispatchReader("ReadBanchoMessage", func(c chio.BanchoIO) (func(io.Reader) (*chio.Message, error), bool) {
+		if h, ok := c.(BanchoMessageReader); ok {
+			return h.ReadBanchoMessage, true
+		}
+		return nil, false
+	})
+}
+
+func ReaderReadUserStats() chio.PacketReader {
+	return dispatchReader("ReadUserStats", func(c chio.BanchoIO) (func(io.Reader) (*chio.UserInfo, error), bool) {
+		if h, ok := c.(UserStatsReader); ok {
+			return h.ReadUserStats, true
+		}
+		return nil, false
+	})
+}
+
+func ReaderReadUserQuit() chio.PacketReader {
+	return dispatchReader("ReadUserQuit", func(c chio.BanchoIO) (func(io.Reader) (*chio.UserQuit, error), bool) {
+		if h, ok := c.(UserQuitReader); ok {
+			return h.ReadUserQuit, true
+		}
+		return nil, false
+	})
+}
+
 // Simple readers for primitive types, e.g. bInt or bString
 
 func ReaderReadBanchoInt() chio.PacketReader {
@@ -149,6 +186,106 @@ func ReaderReadBanchoString() chio.PacketReader {
 	}
 }
 
+// Readers for packets that wrap a single primitive value
+
+// readerReadInt creates a PacketReader that wraps a bInt into a packet struct
+func readerReadInt[T any](wrap func(int32) *T) chio.PacketReader {
+	return func(_ chio.BanchoIO, r io.Reader) (any, error) {
+		value, err := ReadInt32(r)
+		if err != nil {
+			return nil, err
+		}
+		return wrap(value), nil
+	}
+}
+
+// readerReadString creates a PacketReader that wraps a bString into a packet struct
+func readerReadString[T any](wrap func(string) *T) chio.PacketReader {
+	return func(_ chio.BanchoIO, r io.Reader) (any, error) {
+		value, err := ReadString(r)
+		if err != nil {
+			return nil, err
+		}
+		return wrap(value), nil
+	}
+}
+
+func ReaderReadStartSpectating() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.StartSpectating { return &chio.StartSpectating{UserId: v} })
+}
+
+func ReaderReadErrorReport() chio.PacketReader {
+	return readerReadString(func(v string) *chio.ErrorReport { return &chio.ErrorReport{Text: v} })
+}
+
+func ReaderReadChannelJoin() chio.PacketReader {
+	return readerReadString(func(v string) *chio.ChannelJoin { return &chio.ChannelJoin{Name: v} })
+}
+
+func ReaderReadChannelLeave() chio.PacketReader {
+	return readerReadString(func(v string) *chio.ChannelLeave { return &chio.ChannelLeave{Name: v} })
+}
+
+func ReaderReadLoginReply() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.LoginReply { return &chio.LoginReply{Reply: v} })
+}
+
+func ReaderReadSpectatorJoined() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.SpectatorJoined { return &chio.SpectatorJoined{UserId: v} })
+}
+
+func ReaderReadSpectatorLeft() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.SpectatorLeft { return &chio.SpectatorLeft{UserId: v} })
+}
+
+func ReaderReadSpectatorCantSpectate() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.SpectatorCantSpectate { return &chio.SpectatorCantSpectate{UserId: v} })
+}
+
+func ReaderReadFellowSpectatorJoined() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.FellowSpectatorJoined { return &chio.FellowSpectatorJoined{UserId: v} })
+}
+
+func ReaderReadFellowSpectatorLeft() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.FellowSpectatorLeft { return &chio.FellowSpectatorLeft{UserId: v} })
+}
+
+func ReaderReadMatchDisband() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.MatchDisband { return &chio.MatchDisband{MatchId: v} })
+}
+
+func ReaderReadLobbyJoin() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.LobbyJoin { return &chio.LobbyJoin{UserId: v} })
+}
+
+func ReaderReadLobbyPart() chio.PacketReader {
+	return readerReadInt(func(v int32) *chio.LobbyPart { return &chio.LobbyPart{UserId: v} })
+}
+
+func ReaderReadAnnouncement() chio.PacketReader {
+	return readerReadString(func(v string) *chio.Announcement { return &chio.Announcement{Text: v} })
+}
+
+func ReaderReadIrcChangeUsername() chio.PacketReader {
+	return readerReadString(func(v string) *chio.IrcChangeUsername {
+		oldName, newName, _ := strings.Cut(v, ">>>>")
+		return &chio.IrcChangeUsername{OldName: oldName, NewName: newName}
+	})
+}
+
+func ReaderReadIrcJoin() chio.PacketReader {
+	return readerReadString(func(v string) *chio.UserInfo {
+		return &chio.UserInfo{Name: v, Presence: &chio.UserPresence{IsIrc: true}}
+	})
+}
+
+func ReaderReadIrcQuit() chio.PacketReader {
+	return readerReadString(func(v string) *chio.UserQuit {
+		info := &chio.UserInfo{Name: v, Presence: &chio.UserPresence{IsIrc: true}}
+		return &chio.UserQuit{Info: info, QuitState: chio.QuitStateGone}
+	})
+}
+
 func ReaderReadEmpty() chio.PacketReader {
 	return func(_ chio.BanchoIO, r io.Reader) (any, error) {
 		return nil, nil
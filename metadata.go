@@ -0,0 +1,215 @@
+package chio
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Direction describes which side of the connection sends a packet
+type Direction uint8
+
+const (
+	DirectionClientToServer Direction = iota
+	DirectionServerToClient
+
+	// DirectionUnknown is returned for packet ids without metadata
+	DirectionUnknown
+)
+
+func (d Direction) String() string {
+	switch d {
+	case DirectionClientToServer:
+		return "client->server"
+	case DirectionServerToClient:
+		return "server->client"
+	}
+	return "unknown"
+}
+
+// PacketInfo holds the metadata of a packet id
+type PacketInfo struct {
+	Id        uint16
+	Name      string
+	Direction Direction
+
+	// Payload is the type that the packet data is decoded into or
+	// written from, or nil if the packet is empty or not decoded
+	Payload reflect.Type
+}
+
+// String returns the name of the packet
+func (info PacketInfo) String() string {
+	return info.Name
+}
+
+// Versions returns every registered client version that implements the packet
+func (info PacketInfo) Versions() []int {
+	return PacketVersions(info.Id)
+}
+
+var packets = map[uint16]PacketInfo{
+	OsuSendUserStatus:              {Id: OsuSendUserStatus, Name: "OsuSendUserStatus", Direction: DirectionClientToServer, Payload: reflect.TypeFor[UserStatus]()},
+	OsuSendIrcMessage:              {Id: OsuSendIrcMessage, Name: "OsuSendIrcMessage", Direction: DirectionClientToServer, Payload: reflect.TypeFor[Message]()},
+	OsuExit:                        {Id: OsuExit, Name: "OsuExit", Direction: DirectionClientToServer},
+	OsuRequestStatusUpdate:         {Id: OsuRequestStatusUpdate, Name: "OsuRequestStatusUpdate", Direction: DirectionClientToServer},
+	OsuPong:                        {Id: OsuPong, Name: "OsuPong", Direction: DirectionClientToServer},
+	BanchoLoginReply:               {Id: BanchoLoginReply, Name: "BanchoLoginReply", Direction: DirectionServerToClient, Payload: reflect.TypeFor[LoginReply]()},
+	BanchoCommandError:             {Id: BanchoCommandError, Name: "BanchoCommandError", Direction: DirectionServerToClient},
+	BanchoSendMessage:              {Id: BanchoSendMessage, Name: "BanchoSendMessage", Direction: DirectionServerToClient, Payload: reflect.TypeFor[Message]()},
+	BanchoPing:                     {Id: BanchoPing, Name: "BanchoPing", Direction: DirectionServerToClient},
+	BanchoHandleIrcChangeUsername:  {Id: BanchoHandleIrcChangeUsername, Name: "BanchoHandleIrcChangeUsername", Direction: DirectionServerToClient, Payload: reflect.TypeFor[IrcChangeUsername]()},
+	BanchoHandleIrcQuit:            {Id: BanchoHandleIrcQuit, Name: "BanchoHandleIrcQuit", Direction: DirectionServerToClient, Payload: reflect.TypeFor[UserQuit]()},
+	BanchoHandleOsuUpdate:          {Id: BanchoHandleOsuUpdate, Name: "BanchoHandleOsuUpdate", Direction: DirectionServerToClient, Payload: reflect.TypeFor[UserInfo]()},
+	BanchoHandleOsuQuit:            {Id: BanchoHandleOsuQuit, Name: "BanchoHandleOsuQuit", Direction: DirectionServerToClient, Payload: reflect.TypeFor[UserQuit]()},
+	BanchoSpectatorJoined:          {Id: BanchoSpectatorJoined, Name: "BanchoSpectatorJoined", Direction: DirectionServerToClient, Payload: reflect.TypeFor[SpectatorJoined]()},
+	BanchoSpectatorLeft:            {Id: BanchoSpectatorLeft, Name: "BanchoSpectatorLeft", Direction: DirectionServerToClient, Payload: reflect.TypeFor[SpectatorLeft]()},
+	BanchoSpectateFrames:           {Id: BanchoSpectateFrames, Name: "BanchoSpectateFrames", Direction: DirectionServerToClient, Payload: reflect.TypeFor[ReplayFrameBundle]()},
+	OsuStartSpectating:             {Id: OsuStartSpectating, Name: "OsuStartSpectating", Direction: DirectionClientToServer, Payload: reflect.TypeFor[StartSpectating]()},
+	OsuStopSpectating:              {Id: OsuStopSpectating, Name: "OsuStopSpectating", Direction: DirectionClientToServer},
+	OsuSpectateFrames:              {Id: OsuSpectateFrames, Name: "OsuSpectateFrames", Direction: DirectionClientToServer, Payload: reflect.TypeFor[ReplayFrameBundle]()},
+	BanchoVersionUpdate:            {Id: BanchoVersionUpdate, Name: "BanchoVersionUpdate", Direction: DirectionServerToClient},
+	OsuErrorReport:                 {Id: OsuErrorReport, Name: "OsuErrorReport", Direction: DirectionClientToServer, Payload: reflect.TypeFor[ErrorReport]()},
+	OsuCantSpectate:                {Id: OsuCantSpectate, Name: "OsuCantSpectate", Direction: DirectionClientToServer},
+	BanchoSpectatorCantSpectate:    {Id: BanchoSpectatorCantSpectate, Name: "BanchoSpectatorCantSpectate", Direction: DirectionServerToClient, Payload: reflect.TypeFor[SpectatorCantSpectate]()},
+	BanchoGetAttention:             {Id: BanchoGetAttention, Name: "BanchoGetAttention", Direction: DirectionServerToClient},
+	BanchoAnnounce:                 {Id: BanchoAnnounce, Name: "BanchoAnnounce", Direction: DirectionServerToClient, Payload: reflect.TypeFor[Announcement]()},
+	OsuSendIrcMessagePrivate:       {Id: OsuSendIrcMessagePrivate, Name: "OsuSendIrcMessagePrivate", Direction: DirectionClientToServer, Payload: reflect.TypeFor[Message]()},
+	BanchoMatchUpdate:              {Id: BanchoMatchUpdate, Name: "BanchoMatchUpdate", Direction: DirectionServerToClient, Payload: reflect.TypeFor[Match]()},
+	BanchoMatchNew:                 {Id: BanchoMatchNew, Name: "BanchoMatchNew", Direction: DirectionServerToClient, Payload: reflect.TypeFor[Match]()},
+	BanchoMatchDisband:             {Id: BanchoMatchDisband, Name: "BanchoMatchDisband", Direction: DirectionServerToClient, Payload: reflect.TypeFor[MatchDisband]()},
+	OsuLobbyPart:                   {Id: OsuLobbyPart, Name: "OsuLobbyPart", Direction: DirectionClientToServer},
+	OsuLobbyJoin:                   {Id: OsuLobbyJoin, Name: "OsuLobbyJoin", Direction: DirectionClientToServer},
+	OsuMatchCreate:                 {Id: OsuMatchCreate, Name: "OsuMatchCreate", Direction: DirectionClientToServer, Payload: reflect.TypeFor[Match]()},
+	OsuMatchJoin:                   {Id: OsuMatchJoin, Name: "OsuMatchJoin", Direction: DirectionClientToServer, Payload: reflect.TypeFor[MatchJoin]()},
+	OsuMatchPart:                   {Id: OsuMatchPart, Name: "OsuMatchPart", Direction: DirectionClientToServer},
+	BanchoLobbyJoin:                {Id: BanchoLobbyJoin, Name: "BanchoLobbyJoin", Direction: DirectionServerToClient, Payload: reflect.TypeFor[LobbyJoin]()},
+	BanchoLobbyPart:                {Id: BanchoLobbyPart, Name: "BanchoLobbyPart", Direction: DirectionServerToClient, Payload: reflect.TypeFor[LobbyPart]()},
+	BanchoMatchJoinSuccess:         {Id: BanchoMatchJoinSuccess, Name: "BanchoMatchJoinSuccess", Direction: DirectionServerToClient, Payload: reflect.TypeFor[Match]()},
+	BanchoMatchJoinFail:            {Id: BanchoMatchJoinFail, Name: "BanchoMatchJoinFail", Direction: DirectionServerToClient},
+	OsuMatchChangeSlot:             {Id: OsuMatchChangeSlot, Name: "OsuMatchChangeSlot", Direction: DirectionClientToServer, Payload: reflect.TypeFor[MatchChangeSlot]()},
+	OsuMatchReady:                  {Id: OsuMatchReady, Name: "OsuMatchReady", Direction: DirectionClientToServer},
+	OsuMatchLock:                   {Id: OsuMatchLock, Name: "OsuMatchLock", Direction: DirectionClientToServer, Payload: reflect.TypeFor[MatchLock]()},
+	OsuMatchChangeSettings:         {Id: OsuMatchChangeSettings, Name: "OsuMatchChangeSettings", Direction: DirectionClientToServer, Payload: reflect.TypeFor[Match]()},
+	BanchoFellowSpectatorJoined:    {Id: BanchoFellowSpectatorJoined, Name: "BanchoFellowSpectatorJoined", Direction: DirectionServerToClient, Payload: reflect.TypeFor[FellowSpectatorJoined]()},
+	BanchoFellowSpectatorLeft:      {Id: BanchoFellowSpectatorLeft, Name: "BanchoFellowSpectatorLeft", Direction: DirectionServerToClient, Payload: reflect.TypeFor[FellowSpectatorLeft]()},
+	OsuMatchStart:                  {Id: OsuMatchStart, Name: "OsuMatchStart", Direction: DirectionClientToServer},
+	BanchoMatchStart:               {Id: BanchoMatchStart, Name: "BanchoMatchStart", Direction: DirectionServerToClient, Payload: reflect.TypeFor[Match]()},
+	OsuMatchScoreUpdate:            {Id: OsuMatchScoreUpdate, Name: "OsuMatchScoreUpdate", Direction: DirectionClientToServer, Payload: reflect.TypeFor[ScoreFrame]()},
+	BanchoMatchScoreUpdate:         {Id: BanchoMatchScoreUpdate, Name: "BanchoMatchScoreUpdate", Direction: DirectionServerToClient, Payload: reflect.TypeFor[ScoreFrame]()},
+	OsuMatchComplete:               {Id: OsuMatchComplete, Name: "OsuMatchComplete", Direction: DirectionClientToServer},
+	BanchoMatchTransferHost:        {Id: BanchoMatchTransferHost, Name: "BanchoMatchTransferHost", Direction: DirectionServerToClient},
+	OsuMatchChangeMods:             {Id: OsuMatchChangeMods, Name: "OsuMatchChangeMods", Direction: DirectionClientToServer},
+	OsuMatchLoadComplete:           {Id: OsuMatchLoadComplete, Name: "OsuMatchLoadComplete", Direction: DirectionClientToServer},
+	BanchoMatchAllPlayersLoaded:    {Id: BanchoMatchAllPlayersLoaded, Name: "BanchoMatchAllPlayersLoaded", Direction: DirectionServerToClient},
+	OsuMatchNoBeatmap:              {Id: OsuMatchNoBeatmap, Name: "OsuMatchNoBeatmap", Direction: DirectionClientToServer},
+	OsuMatchNotReady:               {Id: OsuMatchNotReady, Name: "OsuMatchNotReady", Direction: DirectionClientToServer},
+	OsuMatchFailed:                 {Id: OsuMatchFailed, Name: "OsuMatchFailed", Direction: DirectionClientToServer},
+	BanchoMatchPlayerFailed:        {Id: BanchoMatchPlayerFailed, Name: "BanchoMatchPlayerFailed", Direction: DirectionServerToClient},
+	BanchoMatchComplete:            {Id: BanchoMatchComplete, Name: "BanchoMatchComplete", Direction: DirectionServerToClient},
+	OsuMatchHasBeatmap:             {Id: OsuMatchHasBeatmap, Name: "OsuMatchHasBeatmap", Direction: DirectionClientToServer},
+	OsuMatchSkipRequest:            {Id: OsuMatchSkipRequest, Name: "OsuMatchSkipRequest", Direction: DirectionClientToServer},
+	BanchoMatchSkip:                {Id: BanchoMatchSkip, Name: "BanchoMatchSkip", Direction: DirectionServerToClient},
+	BanchoUnauthorized:             {Id: BanchoUnauthorized, Name: "BanchoUnauthorized", Direction: DirectionServerToClient},
+	OsuChannelJoin:                 {Id: OsuChannelJoin, Name: "OsuChannelJoin", Direction: DirectionClientToServer, Payload: reflect.TypeFor[ChannelJoin]()},
+	BanchoChannelJoinSuccess:       {Id: BanchoChannelJoinSuccess, Name: "BanchoChannelJoinSuccess", Direction: DirectionServerToClient},
+	BanchoChannelAvailable:         {Id: BanchoChannelAvailable, Name: "BanchoChannelAvailable", Direction: DirectionServerToClient, Payload: reflect.TypeFor[Channel]()},
+	BanchoChannelRevoked:           {Id: BanchoChannelRevoked, Name: "BanchoChannelRevoked", Direction: DirectionServerToClient},
+	BanchoChannelAvailableAutojoin: {Id: BanchoChannelAvailableAutojoin, Name: "BanchoChannelAvailableAutojoin", Direction: DirectionServerToClient, Payload: reflect.TypeFor[Channel]()},
+	OsuBeatmapInfoRequest:          {Id: OsuBeatmapInfoRequest, Name: "OsuBeatmapInfoRequest", Direction: DirectionClientToServer, Payload: reflect.TypeFor[BeatmapInfoRequest]()},
+	BanchoBeatmapInfoReply:         {Id: BanchoBeatmapInfoReply, Name: "BanchoBeatmapInfoReply", Direction: DirectionServerToClient, Payload: reflect.TypeFor[BeatmapInfoReply]()},
+	OsuMatchTransferHost:           {Id: OsuMatchTransferHost, Name: "OsuMatchTransferHost", Direction: DirectionClientToServer},
+	BanchoLoginPermissions:         {Id: BanchoLoginPermissions, Name: "BanchoLoginPermissions", Direction: DirectionServerToClient},
+	BanchoFriendsList:              {Id: BanchoFriendsList, Name: "BanchoFriendsList", Direction: DirectionServerToClient},
+	OsuFriendsAdd:                  {Id: OsuFriendsAdd, Name: "OsuFriendsAdd", Direction: DirectionClientToServer},
+	OsuFriendsRemove:               {Id: OsuFriendsRemove, Name: "OsuFriendsRemove", Direction: DirectionClientToServer},
+	BanchoProtocolNegotiation:      {Id: BanchoProtocolNegotiation, Name: "BanchoProtocolNegotiation", Direction: DirectionServerToClient},
+	BanchoTitleUpdate:              {Id: BanchoTitleUpdate, Name: "BanchoTitleUpdate", Direction: DirectionServerToClient, Payload: reflect.TypeFor[TitleUpdate]()},
+	OsuMatchChangeTeam:             {Id: OsuMatchChangeTeam, Name: "OsuMatchChangeTeam", Direction: DirectionClientToServer},
+	OsuChannelLeave:                {Id: OsuChannelLeave, Name: "OsuChannelLeave", Direction: DirectionClientToServer, Payload: reflect.TypeFor[ChannelLeave]()},
+	OsuReceiveUpdates:              {Id: OsuReceiveUpdates, Name: "OsuReceiveUpdates", Direction: DirectionClientToServer},
+	BanchoMonitor:                  {Id: BanchoMonitor, Name: "BanchoMonitor", Direction: DirectionServerToClient},
+	BanchoMatchPlayerSkipped:       {Id: BanchoMatchPlayerSkipped, Name: "BanchoMatchPlayerSkipped", Direction: DirectionServerToClient},
+	OsuSetIrcAwayMessage:           {Id: OsuSetIrcAwayMessage, Name: "OsuSetIrcAwayMessage", Direction: DirectionClientToServer},
+	BanchoUserPresence:             {Id: BanchoUserPresence, Name: "BanchoUserPresence", Direction: DirectionServerToClient, Payload: reflect.TypeFor[UserInfo]()},
+	OsuUserStatsRequest:            {Id: OsuUserStatsRequest, Name: "OsuUserStatsRequest", Direction: DirectionClientToServer},
+	BanchoRestart:                  {Id: BanchoRestart, Name: "BanchoRestart", Direction: DirectionServerToClient},
+	OsuInvite:                      {Id: OsuInvite, Name: "OsuInvite", Direction: DirectionClientToServer},
+	BanchoInvite:                   {Id: BanchoInvite, Name: "BanchoInvite", Direction: DirectionServerToClient, Payload: reflect.TypeFor[Message]()},
+	BanchoChannelInfoComplete:      {Id: BanchoChannelInfoComplete, Name: "BanchoChannelInfoComplete", Direction: DirectionServerToClient},
+	OsuMatchChangePassword:         {Id: OsuMatchChangePassword, Name: "OsuMatchChangePassword", Direction: DirectionClientToServer},
+	BanchoMatchChangePassword:      {Id: BanchoMatchChangePassword, Name: "BanchoMatchChangePassword", Direction: DirectionServerToClient},
+	BanchoSilenceInfo:              {Id: BanchoSilenceInfo, Name: "BanchoSilenceInfo", Direction: DirectionServerToClient},
+	OsuTournamentMatchInfo:         {Id: OsuTournamentMatchInfo, Name: "OsuTournamentMatchInfo", Direction: DirectionClientToServer},
+	BanchoUserSilenced:             {Id: BanchoUserSilenced, Name: "BanchoUserSilenced", Direction: DirectionServerToClient},
+	BanchoUserPresenceSingle:       {Id: BanchoUserPresenceSingle, Name: "BanchoUserPresenceSingle", Direction: DirectionServerToClient, Payload: reflect.TypeFor[UserInfo]()},
+	BanchoUserPresenceBundle:       {Id: BanchoUserPresenceBundle, Name: "BanchoUserPresenceBundle", Direction: DirectionServerToClient},
+	OsuPresenceRequest:             {Id: OsuPresenceRequest, Name: "OsuPresenceRequest", Direction: DirectionClientToServer},
+	OsuPresenceRequestAll:          {Id: OsuPresenceRequestAll, Name: "OsuPresenceRequestAll", Direction: DirectionClientToServer},
+	OsuChangeFriendOnlyDMs:         {Id: OsuChangeFriendOnlyDMs, Name: "OsuChangeFriendOnlyDMs", Direction: DirectionClientToServer},
+	BanchoUserDMsBlocked:           {Id: BanchoUserDMsBlocked, Name: "BanchoUserDMsBlocked", Direction: DirectionServerToClient},
+	BanchoTargetIsSilenced:         {Id: BanchoTargetIsSilenced, Name: "BanchoTargetIsSilenced", Direction: DirectionServerToClient},
+	BanchoVersionUpdateForced:      {Id: BanchoVersionUpdateForced, Name: "BanchoVersionUpdateForced", Direction: DirectionServerToClient},
+	BanchoSwitchServer:             {Id: BanchoSwitchServer, Name: "BanchoSwitchServer", Direction: DirectionServerToClient},
+	BanchoAccountRestricted:        {Id: BanchoAccountRestricted, Name: "BanchoAccountRestricted", Direction: DirectionServerToClient},
+	BanchoRTX:                      {Id: BanchoRTX, Name: "BanchoRTX", Direction: DirectionServerToClient},
+	BanchoMatchAbort:               {Id: BanchoMatchAbort, Name: "BanchoMatchAbort", Direction: DirectionServerToClient},
+	BanchoSwitchTournamentServer:   {Id: BanchoSwitchTournamentServer, Name: "BanchoSwitchTournamentServer", Direction: DirectionServerToClient},
+	OsuTournamentJoinMatchChannel:  {Id: OsuTournamentJoinMatchChannel, Name: "OsuTournamentJoinMatchChannel", Direction: DirectionClientToServer},
+	OsuTournamentLeaveMatchChannel: {Id: OsuTournamentLeaveMatchChannel, Name: "OsuTournamentLeaveMatchChannel", Direction: DirectionClientToServer},
+	BanchoHandleIrcJoin:            {Id: BanchoHandleIrcJoin, Name: "BanchoHandleIrcJoin", Direction: DirectionServerToClient, Payload: reflect.TypeFor[UserInfo]()},
+	OsuMatchChangeBeatmap:          {Id: OsuMatchChangeBeatmap, Name: "OsuMatchChangeBeatmap", Direction: DirectionClientToServer, Payload: reflect.TypeFor[Match]()},
+}
+
+// GetPacketInfo returns the metadata of a packet id
+func GetPacketInfo(packetId uint16) (PacketInfo, bool) {
+	info, ok := packets[packetId]
+	return info, ok
+}
+
+// Packets returns the metadata of every known packet, sorted by id
+func Packets() []PacketInfo {
+	infos := make([]PacketInfo, 0, len(packets))
+	for _, info := range packets {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Id < infos[j].Id
+	})
+	return infos
+}
+
+// PacketName returns the name of a packet id, e.g. "OsuMatchScoreUpdate"
+func PacketName(packetId uint16) string {
+	if info, ok := packets[packetId]; ok {
+		return info.Name
+	}
+	return fmt.Sprintf("Unknown(%d)", packetId)
+}
+
+// PacketDirection returns the direction in which a packet id is sent,
+// or DirectionUnknown if the packet id is unknown
+func PacketDirection(packetId uint16) Direction {
+	if info, ok := packets[packetId]; ok {
+		return info.Direction
+	}
+	return DirectionUnknown
+}
+
+// PacketVersions returns every registered client version that implements the packet
+func PacketVersions(packetId uint16) []int {
+	versions := make([]int, 0)
+	for _, version := range DefaultRegistry.Versions() {
+		if client, ok := DefaultRegistry.Get(version); ok && client.ImplementsPacket(packetId) {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+func (packet *BanchoPacket) String() string {
+	if packet.Data == nil {
+		return fmt.Sprintf("%s(%d)", PacketName(packet.Id), packet.Id)
+	}
+	return fmt.Sprintf("%s(%d) %+v", PacketName(packet.Id), packet.Id, packet.Data)
+}
@@ -0,0 +1,54 @@
+package chio_test
+
+import (
+	"strings"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+func TestPacketMetadata(t *testing.T) {
+	packets := chio.Packets()
+	if len(packets) != 110 {
+		t.Errorf("expected 110 packets, got %d", len(packets))
+	}
+
+	names := make(map[string]bool)
+	for _, info := range packets {
+		if info.Name == "" || names[info.Name] {
+			t.Errorf("packet %d: expected a unique name, got %q", info.Id, info.Name)
+		}
+		names[info.Name] = true
+
+		if name := chio.PacketName(info.Id); name != info.Name {
+			t.Errorf("packet %d: expected the name %q, got %q", info.Id, info.Name, name)
+		}
+
+		var expected chio.Direction
+		switch {
+		case strings.HasPrefix(info.Name, "Osu"):
+			expected = chio.DirectionClientToServer
+		case strings.HasPrefix(info.Name, "Bancho"):
+			expected = chio.DirectionServerToClient
+		default:
+			t.Errorf("%s: expected the name to start with Osu or Bancho", info.Name)
+			continue
+		}
+
+		if info.Direction != expected || chio.PacketDirection(info.Id) != expected {
+			t.Errorf("%s: expected the direction %s, got %s", info.Name, expected, info.Direction)
+		}
+	}
+}
+
+func TestUnknownPacketMetadata(t *testing.T) {
+	if _, ok := chio.GetPacketInfo(1337); ok {
+		t.Fatal("expected packet 1337 to be unknown")
+	}
+	if direction := chio.PacketDirection(1337); direction != chio.DirectionUnknown {
+		t.Fatalf("expected an unknown direction, got %s", direction)
+	}
+	if name := chio.PacketName(1337); name != "Unknown(1337)" {
+		t.Fatalf("unexpected name %q", name)
+	}
+}
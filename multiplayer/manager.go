@@ -0,0 +1,843 @@
+// Package multiplayer implements the lobby & match state of the bancho protocol.
+//
+// A server adds every connected player to a Manager, and passes the lobby &
+// match packets it reads from them to Handle. The manager validates them
+// against the state of the match, and sends the resulting packets to the
+// players of the match and the lobby:
+//
+//	manager.Add(&chio.Session{Id: user.Id, Name: user.Name, IO: io, Stream: conn})
+//	defer manager.Remove(user.Id)
+//
+//	for {
+//		packet, err := io.ReadPacket(conn)
+//		...
+//		manager.Handle(user.Id, packet)
+//	}
+package multiplayer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+var (
+	ErrUnknownPlayer     = errors.New("unknown player")
+	ErrUnknownMatch      = errors.New("unknown match")
+	ErrNotInMatch        = errors.New("player is not in a match")
+	ErrAlreadyInMatch    = errors.New("player is already in a match")
+	ErrNotHost           = errors.New("player is not the host of the match")
+	ErrInProgress        = errors.New("match is in progress")
+	ErrNotInProgress     = errors.New("match is not in progress")
+	ErrWrongPassword     = errors.New("wrong password")
+	ErrMatchFull         = errors.New("match is full")
+	ErrSlotUnavailable   = errors.New("slot is not available")
+	ErrNotReady          = errors.New("not every player is ready")
+	ErrInvalidTransition = errors.New("invalid slot transition")
+)
+
+// matchState is a match, and the progress of its players while it is being played
+type matchState struct {
+	*chio.Match
+	loaded    map[int32]bool
+	skipped   map[int32]bool
+	allLoaded bool
+}
+
+// Manager tracks the lobby, matches and their players
+type Manager struct {
+	players map[int32]*chio.Session
+	lobby   map[int32]bool
+	matches map[int32]*matchState
+	joined  map[int32]int32 // Match that a player is in
+	mu      sync.Mutex
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		players: make(map[int32]*chio.Session),
+		lobby:   make(map[int32]bool),
+		matches: make(map[int32]*matchState),
+		joined:  make(map[int32]int32),
+	}
+}
+
+// Add registers a player, replacing a previous player with the same id
+func (manager *Manager) Add(player *chio.Session) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	manager.players[player.Id] = player
+}
+
+// Remove handles the disconnect of a player, which leaves its match and the lobby
+func (manager *Manager) Remove(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		errs := make([]error, 0)
+		if _, ok := manager.joined[id]; ok {
+			errs = append(errs, manager.part(outbox, id))
+		}
+		if manager.lobby[id] {
+			errs = append(errs, manager.partLobby(outbox, id))
+		}
+		delete(manager.players, id)
+		return errors.Join(errs...)
+	})
+}
+
+// Handle applies a lobby or match packet that was read from a player. Other packets are ignored.
+func (manager *Manager) Handle(id int32, packet *chio.BanchoPacket) error {
+	switch packet.Id {
+	case chio.OsuLobbyJoin:
+		return manager.JoinLobby(id)
+	case chio.OsuLobbyPart:
+		return manager.PartLobby(id)
+	case chio.OsuMatchCreate:
+		match, err := chio.Decode[chio.Match](packet)
+		if err != nil {
+			return err
+		}
+		_, err = manager.Create(id, match)
+		return err
+	case chio.OsuMatchJoin:
+		join, err := chio.Decode[chio.MatchJoin](packet)
+		if err != nil {
+			return err
+		}
+		return manager.Join(id, join)
+	case chio.OsuMatchPart:
+		return manager.Part(id)
+	case chio.OsuMatchChangeSlot:
+		change, err := chio.Decode[chio.MatchChangeSlot](packet)
+		if err != nil {
+			return err
+		}
+		return manager.ChangeSlot(id, int(change.SlotId))
+	case chio.OsuMatchReady:
+		return manager.SetStatus(id, chio.SlotStatusReady)
+	case chio.OsuMatchNotReady, chio.OsuMatchHasBeatmap:
+		return manager.SetStatus(id, chio.SlotStatusNotReady)
+	case chio.OsuMatchNoBeatmap:
+		return manager.SetStatus(id, chio.SlotStatusNoMap)
+	case chio.OsuMatchLock:
+		lock, err := chio.Decode[chio.MatchLock](packet)
+		if err != nil {
+			return err
+		}
+		return manager.Lock(id, int(lock.SlotId))
+	case chio.OsuMatchChangeSettings:
+		settings, err := chio.Decode[chio.Match](packet)
+		if err != nil {
+			return err
+		}
+		return manager.ChangeSettings(id, settings)
+	case chio.OsuMatchChangeBeatmap:
+		settings, err := chio.Decode[chio.Match](packet)
+		if err != nil {
+			return err
+		}
+		return manager.ChangeBeatmap(id, settings)
+	case chio.OsuMatchStart:
+		return manager.Start(id)
+	case chio.OsuMatchLoadComplete:
+		return manager.LoadComplete(id)
+	case chio.OsuMatchScoreUpdate:
+		frame, err := chio.Decode[chio.ScoreFrame](packet)
+		if err != nil {
+			return err
+		}
+		return manager.ScoreUpdate(id, frame)
+	case chio.OsuMatchFailed:
+		return manager.Failed(id)
+	case chio.OsuMatchSkipRequest:
+		return manager.Skip(id)
+	case chio.OsuMatchComplete:
+		return manager.Complete(id)
+	}
+	return nil
+}
+
+// JoinLobby adds a player to the lobby, which receives every match and their updates
+func (manager *Manager) JoinLobby(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		player, ok := manager.players[id]
+		if !ok {
+			return fmt.Errorf("player %d: %w", id, ErrUnknownPlayer)
+		}
+		if manager.lobby[id] {
+			return nil
+		}
+
+		errs := make([]error, 0)
+		for _, other := range manager.lobbyPlayers() {
+			errs = append(errs, other.IO.WriteLobbyJoin(outbox.Stream(other), id))
+		}
+		manager.lobby[id] = true
+
+		for _, state := range manager.sortedMatches() {
+			errs = append(errs, player.IO.WriteMatchNew(outbox.Stream(player), *state.Match))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// PartLobby removes a player from the lobby
+func (manager *Manager) PartLobby(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		if !manager.lobby[id] {
+			return nil
+		}
+		return manager.partLobby(outbox, id)
+	})
+}
+
+func (manager *Manager) partLobby(outbox *chio.Outbox, id int32) error {
+	delete(manager.lobby, id)
+
+	errs := make([]error, 0)
+	for _, other := range manager.lobbyPlayers() {
+		errs = append(errs, other.IO.WriteLobbyPart(outbox.Stream(other), id))
+	}
+	return errors.Join(errs...)
+}
+
+// Create opens a new match with the settings of the provided one, hosted by the player
+func (manager *Manager) Create(id int32, settings chio.Match) (int32, error) {
+	var matchId int32
+
+	err := chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		player, ok := manager.players[id]
+		if !ok {
+			return fmt.Errorf("player %d: %w", id, ErrUnknownPlayer)
+		}
+		if _, ok := manager.joined[id]; ok {
+			return ErrAlreadyInMatch
+		}
+
+		match := &chio.Match{Id: manager.nextMatchId(), HostId: id}
+		applySettings(match, settings)
+		applyBeatmap(match, settings)
+
+		for i := 0; i < player.IO.MatchSlotSize(); i++ {
+			match.Slots = append(match.Slots, &chio.MatchSlot{Status: chio.SlotStatusOpen})
+		}
+		if len(match.Slots) == 0 {
+			return ErrMatchFull
+		}
+		transition(match.Slots[0], chio.SlotStatusNotReady)
+		match.Slots[0].UserId = id
+
+		manager.matches[match.Id] = &matchState{Match: match}
+		manager.joined[id] = match.Id
+		matchId = match.Id
+
+		errs := []error{player.IO.WriteMatchJoinSuccess(outbox.Stream(player), *match)}
+		for _, other := range manager.lobbyPlayers() {
+			if other.Id != id {
+				errs = append(errs, other.IO.WriteMatchNew(outbox.Stream(other), *match))
+			}
+		}
+		return errors.Join(errs...)
+	})
+	return matchId, err
+}
+
+// Join lets a player join a match, or sends a join failure if that's not possible
+func (manager *Manager) Join(id int32, join chio.MatchJoin) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		player, ok := manager.players[id]
+		if !ok {
+			return fmt.Errorf("player %d: %w", id, ErrUnknownPlayer)
+		}
+		fail := func(err error) error {
+			return errors.Join(err, player.IO.WriteMatchJoinFail(outbox.Stream(player)))
+		}
+
+		if _, ok := manager.joined[id]; ok {
+			return fail(ErrAlreadyInMatch)
+		}
+		state, ok := manager.matches[join.MatchId]
+		if !ok {
+			return fail(fmt.Errorf("match %d: %w", join.MatchId, ErrUnknownMatch))
+		}
+		if state.Password != "" && state.Password != join.Password {
+			return fail(ErrWrongPassword)
+		}
+		if state.InProgress {
+			return fail(ErrInProgress)
+		}
+
+		index := slotIndex(state.Match, func(slot *chio.MatchSlot) bool {
+			return slot.Status == chio.SlotStatusOpen
+		})
+		if index < 0 {
+			return fail(ErrMatchFull)
+		}
+
+		slot := state.Slots[index]
+		transition(slot, chio.SlotStatusNotReady)
+		slot.UserId = id
+		manager.joined[id] = state.Id
+
+		errs := []error{player.IO.WriteMatchJoinSuccess(outbox.Stream(player), *state.Match)}
+		errs = append(errs, manager.broadcast(outbox, state))
+		return errors.Join(errs...)
+	})
+}
+
+// Part lets a player leave its match. Empty matches are disbanded,
+// and the host is transferred to the next player if the host leaves.
+func (manager *Manager) Part(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		return manager.part(outbox, id)
+	})
+}
+
+func (manager *Manager) part(outbox *chio.Outbox, id int32) error {
+	state, slot, err := manager.matchOf(id)
+	if err != nil {
+		return err
+	}
+	delete(manager.joined, id)
+
+	// Players that leave during the match stay visible until it is complete
+	if slot.Status == chio.SlotStatusPlaying || slot.Status == chio.SlotStatusComplete {
+		transition(slot, chio.SlotStatusQuit)
+	} else {
+		transition(slot, chio.SlotStatusOpen)
+	}
+
+	members := manager.members(state)
+	if len(members) == 0 {
+		return manager.disband(outbox, state)
+	}
+
+	errs := make([]error, 0)
+	if state.HostId == id {
+		state.HostId = members[0].Id
+		errs = append(errs, members[0].IO.WriteMatchTransferHost(outbox.Stream(members[0])))
+	}
+	if state.InProgress {
+		errs = append(errs, manager.checkLoaded(outbox, state), manager.checkSkipped(outbox, state))
+		if done, err := manager.checkComplete(outbox, state); done {
+			return errors.Join(append(errs, err)...)
+		}
+	}
+	errs = append(errs, manager.broadcast(outbox, state))
+	return errors.Join(errs...)
+}
+
+func (manager *Manager) disband(outbox *chio.Outbox, state *matchState) error {
+	delete(manager.matches, state.Id)
+
+	errs := make([]error, 0)
+	for _, other := range manager.lobbyPlayers() {
+		errs = append(errs, other.IO.WriteMatchDisband(outbox.Stream(other), state.Id))
+	}
+	return errors.Join(errs...)
+}
+
+// ChangeSlot moves a player to another open slot of its match
+func (manager *Manager) ChangeSlot(id int32, slotId int) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, slot, err := manager.matchOf(id)
+		if err != nil {
+			return err
+		}
+		if state.InProgress {
+			return ErrInProgress
+		}
+		if slotId < 0 || slotId >= len(state.Slots) || state.Slots[slotId].Status != chio.SlotStatusOpen {
+			return fmt.Errorf("slot %d: %w", slotId, ErrSlotUnavailable)
+		}
+
+		*state.Slots[slotId] = *slot
+		transition(slot, chio.SlotStatusOpen)
+		return manager.broadcast(outbox, state)
+	})
+}
+
+// SetStatus changes the status of a player's slot, e.g. when it gets ready or is missing the beatmap
+func (manager *Manager) SetStatus(id int32, status uint8) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, slot, err := manager.matchOf(id)
+		if err != nil {
+			return err
+		}
+		if state.InProgress {
+			return ErrInProgress
+		}
+		if slot.Status == status {
+			return nil
+		}
+		if status&(chio.SlotStatusNotReady|chio.SlotStatusReady|chio.SlotStatusNoMap) == 0 {
+			return fmt.Errorf("%w to %s by the player", ErrInvalidTransition, StatusName(status))
+		}
+		if err := transition(slot, status); err != nil {
+			return err
+		}
+		return manager.broadcast(outbox, state)
+	})
+}
+
+// Lock locks or unlocks a slot of the host's match. Players in a slot that is locked are removed from the match.
+func (manager *Manager) Lock(id int32, slotId int) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, err := manager.hostedMatch(id)
+		if err != nil {
+			return err
+		}
+		if state.InProgress {
+			return ErrInProgress
+		}
+		if slotId < 0 || slotId >= len(state.Slots) || state.Slots[slotId].UserId == id {
+			return fmt.Errorf("slot %d: %w", slotId, ErrSlotUnavailable)
+		}
+
+		slot := state.Slots[slotId]
+		errs := make([]error, 0)
+
+		switch {
+		case slot.Status == chio.SlotStatusLocked:
+			transition(slot, chio.SlotStatusOpen)
+		case slot.HasPlayer():
+			userId := slot.UserId
+			if err := transition(slot, chio.SlotStatusLocked); err != nil {
+				return err
+			}
+			delete(manager.joined, userId)
+
+			if player, ok := manager.players[userId]; ok {
+				errs = append(errs, player.IO.WriteMatchDisband(outbox.Stream(player), state.Id))
+			}
+		default:
+			if err := transition(slot, chio.SlotStatusLocked); err != nil {
+				return err
+			}
+		}
+
+		errs = append(errs, manager.broadcast(outbox, state))
+		return errors.Join(errs...)
+	})
+}
+
+// ChangeSettings applies the settings of the provided match to the host's match.
+// Players that were ready have to get ready again, if the beatmap was changed.
+func (manager *Manager) ChangeSettings(id int32, settings chio.Match) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, err := manager.hostedMatch(id)
+		if err != nil {
+			return err
+		}
+		if state.InProgress {
+			return ErrInProgress
+		}
+
+		applySettings(state.Match, settings)
+		manager.changeBeatmap(state, settings)
+		return manager.broadcast(outbox, state)
+	})
+}
+
+// ChangeBeatmap only applies the beatmap of the provided match to the host's match
+func (manager *Manager) ChangeBeatmap(id int32, settings chio.Match) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, err := manager.hostedMatch(id)
+		if err != nil {
+			return err
+		}
+		if state.InProgress {
+			return ErrInProgress
+		}
+
+		manager.changeBeatmap(state, settings)
+		return manager.broadcast(outbox, state)
+	})
+}
+
+func (manager *Manager) changeBeatmap(state *matchState, settings chio.Match) {
+	if state.BeatmapChecksum == settings.BeatmapChecksum && state.BeatmapId == settings.BeatmapId {
+		return
+	}
+	applyBeatmap(state.Match, settings)
+
+	for _, slot := range state.Slots {
+		if slot.Status == chio.SlotStatusReady {
+			transition(slot, chio.SlotStatusNotReady)
+		}
+	}
+}
+
+// Start starts the host's match, once every other player is ready.
+// Players without the beatmap don't take part in the match.
+func (manager *Manager) Start(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, err := manager.hostedMatch(id)
+		if err != nil {
+			return err
+		}
+		if state.InProgress {
+			return ErrInProgress
+		}
+		for _, slot := range state.Slots {
+			if slot.Status == chio.SlotStatusNotReady && slot.UserId != id {
+				return ErrNotReady
+			}
+		}
+
+		state.InProgress = true
+		state.loaded = make(map[int32]bool)
+		state.skipped = make(map[int32]bool)
+		state.allLoaded = false
+
+		for _, slot := range state.Slots {
+			if slot.Status == chio.SlotStatusReady || slot.Status == chio.SlotStatusNotReady {
+				transition(slot, chio.SlotStatusPlaying)
+			}
+		}
+
+		errs := make([]error, 0)
+		for _, player := range manager.playing(state, chio.SlotStatusPlaying) {
+			// Versions without the packet can't report that they are loaded
+			if !player.IO.ImplementsPacket(chio.OsuMatchLoadComplete) {
+				state.loaded[player.Id] = true
+			}
+			errs = append(errs, player.IO.WriteMatchStart(outbox.Stream(player), *state.Match))
+		}
+		errs = append(errs, manager.broadcast(outbox, state), manager.checkLoaded(outbox, state))
+		return errors.Join(errs...)
+	})
+}
+
+// LoadComplete marks a player as loaded. Once every player is loaded, they are notified.
+func (manager *Manager) LoadComplete(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, err := manager.playingMatch(id)
+		if err != nil {
+			return err
+		}
+		state.loaded[id] = true
+		return manager.checkLoaded(outbox, state)
+	})
+}
+
+func (manager *Manager) checkLoaded(outbox *chio.Outbox, state *matchState) error {
+	players := manager.playing(state, chio.SlotStatusPlaying)
+	if state.allLoaded || len(players) == 0 {
+		return nil
+	}
+	for _, player := range players {
+		if !state.loaded[player.Id] {
+			return nil
+		}
+	}
+	state.allLoaded = true
+
+	errs := make([]error, 0)
+	for _, player := range players {
+		errs = append(errs, player.IO.WriteMatchAllPlayersLoaded(outbox.Stream(player)))
+	}
+	return errors.Join(errs...)
+}
+
+// ScoreUpdate sends the score of a player to everyone that is playing the match
+func (manager *Manager) ScoreUpdate(id int32, frame chio.ScoreFrame) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, err := manager.playingMatch(id)
+		if err != nil {
+			return err
+		}
+		frame.Id = uint8(slotIndex(state.Match, func(slot *chio.MatchSlot) bool { return slot.UserId == id }))
+
+		errs := make([]error, 0)
+		for _, player := range manager.playing(state, chio.SlotStatusPlaying|chio.SlotStatusComplete) {
+			errs = append(errs, player.IO.WriteMatchScoreUpdate(outbox.Stream(player), frame))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Failed reports to everyone that is playing the match, that the player has failed
+func (manager *Manager) Failed(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, err := manager.playingMatch(id)
+		if err != nil {
+			return err
+		}
+		index := slotIndex(state.Match, func(slot *chio.MatchSlot) bool { return slot.UserId == id })
+
+		errs := make([]error, 0)
+		for _, player := range manager.playing(state, chio.SlotStatusPlaying|chio.SlotStatusComplete) {
+			errs = append(errs, player.IO.WriteMatchPlayerFailed(outbox.Stream(player), uint32(index)))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Skip marks a player as wanting to skip the intro. Once every player does, it is skipped.
+func (manager *Manager) Skip(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, err := manager.playingMatch(id)
+		if err != nil {
+			return err
+		}
+		state.skipped[id] = true
+		index := slotIndex(state.Match, func(slot *chio.MatchSlot) bool { return slot.UserId == id })
+
+		errs := make([]error, 0)
+		for _, player := range manager.playing(state, chio.SlotStatusPlaying) {
+			errs = append(errs, player.IO.WriteMatchPlayerSkipped(outbox.Stream(player), int32(index)))
+		}
+		errs = append(errs, manager.checkSkipped(outbox, state))
+		return errors.Join(errs...)
+	})
+}
+
+func (manager *Manager) checkSkipped(outbox *chio.Outbox, state *matchState) error {
+	players := manager.playing(state, chio.SlotStatusPlaying)
+	if len(players) == 0 {
+		return nil
+	}
+	for _, player := range players {
+		if !state.skipped[player.Id] {
+			return nil
+		}
+	}
+
+	errs := make([]error, 0)
+	for _, player := range players {
+		errs = append(errs, player.IO.WriteMatchSkip(outbox.Stream(player)))
+		delete(state.skipped, player.Id)
+	}
+	return errors.Join(errs...)
+}
+
+// Complete marks a player as done playing. Once every player is, the match is completed.
+func (manager *Manager) Complete(id int32) error {
+	return chio.Locked(&manager.mu, func(outbox *chio.Outbox) error {
+		state, err := manager.playingMatch(id)
+		if err != nil {
+			return err
+		}
+		_, slot, _ := manager.matchOf(id)
+		if err := transition(slot, chio.SlotStatusComplete); err != nil {
+			return err
+		}
+
+		_, err = manager.checkComplete(outbox, state)
+		return err
+	})
+}
+
+// checkComplete ends the match, if nobody is playing anymore
+func (manager *Manager) checkComplete(outbox *chio.Outbox, state *matchState) (bool, error) {
+	for _, slot := range state.Slots {
+		if slot.Status == chio.SlotStatusPlaying {
+			return false, nil
+		}
+	}
+
+	players := manager.playing(state, chio.SlotStatusComplete)
+	state.InProgress = false
+
+	for _, slot := range state.Slots {
+		switch slot.Status {
+		case chio.SlotStatusComplete:
+			transition(slot, chio.SlotStatusNotReady)
+		case chio.SlotStatusQuit:
+			transition(slot, chio.SlotStatusOpen)
+		}
+	}
+
+	errs := make([]error, 0)
+	for _, player := range players {
+		errs = append(errs, player.IO.WriteMatchComplete(outbox.Stream(player)))
+	}
+	errs = append(errs, manager.broadcast(outbox, state))
+	return true, errors.Join(errs...)
+}
+
+// Match returns a copy of a match
+func (manager *Manager) Match(matchId int32) (chio.Match, bool) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	state, ok := manager.matches[matchId]
+	if !ok {
+		return chio.Match{}, false
+	}
+	return copyMatch(state.Match), true
+}
+
+// Matches returns a copy of every match, ordered by id
+func (manager *Manager) Matches() []chio.Match {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	states := manager.sortedMatches()
+	matches := make([]chio.Match, len(states))
+	for i, state := range states {
+		matches[i] = copyMatch(state.Match)
+	}
+	return matches
+}
+
+// MatchOf returns the id of the match that a player is in
+func (manager *Manager) MatchOf(id int32) (int32, bool) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	matchId, ok := manager.joined[id]
+	return matchId, ok
+}
+
+// broadcast sends the state of a match to its players and the lobby
+func (manager *Manager) broadcast(outbox *chio.Outbox, state *matchState) error {
+	errs := make([]error, 0)
+	for _, player := range manager.members(state) {
+		errs = append(errs, player.IO.WriteMatchUpdate(outbox.Stream(player), *state.Match))
+	}
+	for _, player := range manager.lobbyPlayers() {
+		if _, ok := manager.joined[player.Id]; !ok {
+			errs = append(errs, player.IO.WriteMatchUpdate(outbox.Stream(player), *state.Match))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// matchOf returns the match of a player, and the slot that it is in
+func (manager *Manager) matchOf(id int32) (*matchState, *chio.MatchSlot, error) {
+	matchId, ok := manager.joined[id]
+	if !ok {
+		return nil, nil, ErrNotInMatch
+	}
+	state := manager.matches[matchId]
+
+	for _, slot := range state.Slots {
+		if slot.UserId == id && slot.HasPlayer() {
+			return state, slot, nil
+		}
+	}
+	return nil, nil, ErrNotInMatch
+}
+
+// hostedMatch returns the match of a player, if it is the host
+func (manager *Manager) hostedMatch(id int32) (*matchState, error) {
+	state, _, err := manager.matchOf(id)
+	if err != nil {
+		return nil, err
+	}
+	if state.HostId != id {
+		return nil, ErrNotHost
+	}
+	return state, nil
+}
+
+// playingMatch returns the match of a player, if it is playing in it
+func (manager *Manager) playingMatch(id int32) (*matchState, error) {
+	state, slot, err := manager.matchOf(id)
+	if err != nil {
+		return nil, err
+	}
+	if !state.InProgress {
+		return nil, ErrNotInProgress
+	}
+	if slot.Status != chio.SlotStatusPlaying {
+		return nil, fmt.Errorf("player %d is %s: %w", id, StatusName(slot.Status), ErrInvalidTransition)
+	}
+	return state, nil
+}
+
+// members returns the players of a match, in the order of their slots
+func (manager *Manager) members(state *matchState) []*chio.Session {
+	return manager.playing(state, chio.SlotStatusHasPlayer)
+}
+
+// playing returns the players of a match with one of the statuses, in the order of their slots
+func (manager *Manager) playing(state *matchState, status uint8) []*chio.Session {
+	players := make([]*chio.Session, 0, len(state.Slots))
+	for _, slot := range state.Slots {
+		if slot.Status&status == 0 {
+			continue
+		}
+		if player, ok := manager.players[slot.UserId]; ok && manager.joined[slot.UserId] == state.Id {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+// lobbyPlayers returns the players in the lobby, ordered by id
+func (manager *Manager) lobbyPlayers() []*chio.Session {
+	ids := make([]int32, 0, len(manager.lobby))
+	for id := range manager.lobby {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	players := make([]*chio.Session, 0, len(ids))
+	for _, id := range ids {
+		if player, ok := manager.players[id]; ok {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+func (manager *Manager) sortedMatches() []*matchState {
+	states := make([]*matchState, 0, len(manager.matches))
+	for _, state := range manager.matches {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Id < states[j].Id })
+	return states
+}
+
+// nextMatchId returns the lowest unused match id, since some versions only use a single byte for it
+func (manager *Manager) nextMatchId() int32 {
+	id := int32(1)
+	for {
+		if _, ok := manager.matches[id]; !ok {
+			return id
+		}
+		id++
+	}
+}
+
+func slotIndex(match *chio.Match, matches func(*chio.MatchSlot) bool) int {
+	for i, slot := range match.Slots {
+		if matches(slot) {
+			return i
+		}
+	}
+	return -1
+}
+
+func applySettings(match *chio.Match, settings chio.Match) {
+	match.Name = settings.Name
+	match.Password = settings.Password
+	match.Type = settings.Type
+	match.Mods = settings.Mods
+	match.Mode = settings.Mode
+	match.ScoringType = settings.ScoringType
+	match.TeamType = settings.TeamType
+	match.Freemod = settings.Freemod
+	match.Seed = settings.Seed
+}
+
+func applyBeatmap(match *chio.Match, settings chio.Match) {
+	match.BeatmapText = settings.BeatmapText
+	match.BeatmapId = settings.BeatmapId
+	match.BeatmapChecksum = settings.BeatmapChecksum
+}
+
+func copyMatch(match *chio.Match) chio.Match {
+	copied := *match
+	copied.Slots = make([]*chio.MatchSlot, len(match.Slots))
+	for i, slot := range match.Slots {
+		slot := *slot
+		copied.Slots[i] = &slot
+	}
+	return copied
+}
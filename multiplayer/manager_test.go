@@ -0,0 +1,227 @@
+package multiplayer
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/chiotest"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+func settings() chio.Match {
+	return chio.Match{Name: "test", BeatmapText: "Kenji Ninuma - DISCO PRINCE", BeatmapId: 75, BeatmapChecksum: "a5b9"}
+}
+
+func expectNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCanTransition(t *testing.T) {
+	valid := [][2]uint8{
+		{chio.SlotStatusOpen, chio.SlotStatusNotReady},
+		{chio.SlotStatusNotReady, chio.SlotStatusReady},
+		{chio.SlotStatusReady, chio.SlotStatusPlaying},
+		{chio.SlotStatusPlaying, chio.SlotStatusComplete},
+		{chio.SlotStatusComplete, chio.SlotStatusNotReady},
+		{chio.SlotStatusPlaying, chio.SlotStatusQuit},
+		{chio.SlotStatusQuit, chio.SlotStatusOpen},
+	}
+	invalid := [][2]uint8{
+		{chio.SlotStatusOpen, chio.SlotStatusReady},
+		{chio.SlotStatusLocked, chio.SlotStatusNotReady},
+		{chio.SlotStatusNoMap, chio.SlotStatusReady},
+		{chio.SlotStatusPlaying, chio.SlotStatusOpen},
+		{chio.SlotStatusComplete, chio.SlotStatusPlaying},
+	}
+
+	for _, test := range valid {
+		if !CanTransition(test[0], test[1]) {
+			t.Errorf("expected %s -> %s to be valid", StatusName(test[0]), StatusName(test[1]))
+		}
+	}
+	for _, test := range invalid {
+		if CanTransition(test[0], test[1]) {
+			t.Errorf("expected %s -> %s to be invalid", StatusName(test[0]), StatusName(test[1]))
+		}
+	}
+}
+
+func TestMatchLifecycle(t *testing.T) {
+	manager := NewManager()
+	host := chiotest.NewSession(1, clients.NewB312())
+	manager.Add(host.Session)
+	guest := chiotest.NewSession(2, clients.NewB312())
+	manager.Add(guest.Session)
+	lobby := chiotest.NewSession(3, clients.NewB312())
+	manager.Add(lobby.Session)
+
+	expectNoError(t, manager.JoinLobby(3))
+	matchId, err := manager.Create(1, settings())
+	expectNoError(t, err)
+
+	chiotest.ExpectNext(t, host.Stream, chio.BanchoMatchJoinSuccess)
+	chiotest.ExpectNext(t, lobby.Stream, chio.BanchoMatchNew)
+
+	expectNoError(t, manager.Join(2, chio.MatchJoin{MatchId: matchId}))
+	chiotest.ExpectNext(t, guest.Stream, chio.BanchoMatchJoinSuccess)
+	chiotest.ExpectNext(t, host.Stream, chio.BanchoMatchUpdate)
+	chiotest.ExpectNext(t, lobby.Stream, chio.BanchoMatchUpdate)
+
+	if err := manager.Start(1); !errors.Is(err, ErrNotReady) {
+		t.Fatalf("expected ErrNotReady, got %v", err)
+	}
+	if err := manager.Start(2); !errors.Is(err, ErrNotHost) {
+		t.Fatalf("expected ErrNotHost, got %v", err)
+	}
+
+	expectNoError(t, manager.SetStatus(2, chio.SlotStatusReady))
+	expectNoError(t, manager.Start(1))
+
+	for _, stream := range []*chiotest.Stream{host.Stream, guest.Stream} {
+		chiotest.Expect(t, stream, chio.BanchoMatchStart)
+	}
+	chiotest.ExpectNoPacket(t, lobby.Stream, chio.BanchoMatchStart)
+
+	expectNoError(t, manager.ScoreUpdate(2, chio.ScoreFrame{TotalScore: 1000}))
+	chiotest.ExpectWhere(t, host.Stream, chio.BanchoMatchScoreUpdate, func(frame chio.ScoreFrame) bool {
+		return frame.Id == 1 && frame.TotalScore == 1000
+	})
+
+	expectNoError(t, manager.Complete(1))
+	chiotest.ExpectNone(t, host.Stream)
+	expectNoError(t, manager.Complete(2))
+
+	// b312 has no packet for completed matches, so only the update is sent
+	for _, stream := range []*chiotest.Stream{host.Stream, guest.Stream} {
+		chiotest.ExpectWhere(t, stream, chio.BanchoMatchUpdate, func(match chio.Match) bool { return !match.InProgress })
+		chiotest.ExpectNoError(t, stream)
+	}
+
+	match, _ := manager.Match(matchId)
+	if match.InProgress || match.Slots[0].Status != chio.SlotStatusNotReady || match.Slots[1].Status != chio.SlotStatusNotReady {
+		t.Fatalf("match was not reset after completion: %+v", match)
+	}
+}
+
+func TestMatchPart(t *testing.T) {
+	manager := NewManager()
+	manager.Add(chiotest.NewSession(1, clients.NewB298()).Session)
+	manager.Add(chiotest.NewSession(2, clients.NewB298()).Session)
+	lobby := chiotest.NewSession(3, clients.NewB298())
+	manager.Add(lobby.Session)
+	manager.JoinLobby(3)
+
+	matchId, _ := manager.Create(1, settings())
+	manager.Join(2, chio.MatchJoin{MatchId: matchId})
+
+	expectNoError(t, manager.Part(1))
+	match, _ := manager.Match(matchId)
+	if match.HostId != 2 || match.Slots[0].Status != chio.SlotStatusOpen {
+		t.Fatalf("host was not transferred: %+v", match)
+	}
+
+	expectNoError(t, manager.Remove(2))
+	if _, ok := manager.Match(matchId); ok {
+		t.Fatal("empty match was not disbanded")
+	}
+	chiotest.ExpectWhere(t, lobby.Stream, chio.BanchoMatchDisband, func(disband chio.MatchDisband) bool {
+		return disband.MatchId == matchId
+	})
+}
+
+func TestMatchJoinFail(t *testing.T) {
+	manager := NewManager()
+	manager.Add(chiotest.NewSession(1, clients.NewB298()).Session)
+	guest := chiotest.NewSession(2, clients.NewB298())
+	manager.Add(guest.Session)
+
+	match := settings()
+	match.Password = "secret"
+	matchId, _ := manager.Create(1, match)
+
+	if err := manager.Join(2, chio.MatchJoin{MatchId: matchId}); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("expected ErrWrongPassword, got %v", err)
+	}
+	chiotest.ExpectNext(t, guest.Stream, chio.BanchoMatchJoinFail)
+
+	expectNoError(t, manager.Join(2, chio.MatchJoin{MatchId: matchId, Password: "secret"}))
+	chiotest.ExpectNext(t, guest.Stream, chio.BanchoMatchJoinSuccess)
+}
+
+func TestMatchBeatmapChange(t *testing.T) {
+	manager := NewManager()
+	manager.Add(chiotest.NewSession(1, clients.NewB298()).Session)
+	manager.Add(chiotest.NewSession(2, clients.NewB298()).Session)
+
+	matchId, _ := manager.Create(1, settings())
+	manager.Join(2, chio.MatchJoin{MatchId: matchId})
+	manager.SetStatus(2, chio.SlotStatusReady)
+
+	changed := settings()
+	changed.BeatmapId = 76
+	changed.BeatmapChecksum = "b5c0"
+	expectNoError(t, manager.ChangeSettings(1, changed))
+
+	match, _ := manager.Match(matchId)
+	if match.BeatmapId != 76 || match.Slots[1].Status != chio.SlotStatusNotReady {
+		t.Fatalf("ready players were not reset: %+v", match.Slots[1])
+	}
+	if err := manager.ChangeSettings(2, changed); !errors.Is(err, ErrNotHost) {
+		t.Fatalf("expected ErrNotHost, got %v", err)
+	}
+}
+
+func TestMatchLock(t *testing.T) {
+	manager := NewManager()
+	manager.Add(chiotest.NewSession(1, clients.NewB298()).Session)
+	guest := chiotest.NewSession(2, clients.NewB298())
+	manager.Add(guest.Session)
+
+	matchId, _ := manager.Create(1, settings())
+	manager.Join(2, chio.MatchJoin{MatchId: matchId})
+
+	if err := manager.Lock(1, 0); !errors.Is(err, ErrSlotUnavailable) {
+		t.Fatalf("expected the host to be unable to lock its own slot, got %v", err)
+	}
+	expectNoError(t, manager.Lock(1, 1))
+	chiotest.Expect(t, guest.Stream, chio.BanchoMatchDisband)
+
+	if _, ok := manager.MatchOf(2); ok {
+		t.Fatal("player in a locked slot is still in the match")
+	}
+	match, _ := manager.Match(matchId)
+	if match.Slots[1].Status != chio.SlotStatusLocked {
+		t.Fatalf("expected slot 1 to be locked, got %s", StatusName(match.Slots[1].Status))
+	}
+}
+
+func TestHandle(t *testing.T) {
+	manager := NewManager()
+	server := clients.NewB312()
+	host := chiotest.NewSession(1, server)
+	manager.Add(host.Session)
+
+	client := chiotest.NewClient(server)
+	client.Script(
+		func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteMatchCreate(stream, settings())
+		},
+		func(osu chio.OsuIO, stream io.Writer) error {
+			return osu.WriteMatchStart(stream)
+		},
+	)
+
+	packets, err := client.ReadAll()
+	expectNoError(t, err)
+	for _, packet := range packets {
+		expectNoError(t, manager.Handle(1, packet))
+	}
+
+	chiotest.ExpectNext(t, host.Stream, chio.BanchoMatchJoinSuccess)
+	chiotest.Expect(t, host.Stream, chio.BanchoMatchStart)
+}
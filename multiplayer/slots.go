@@ -0,0 +1,57 @@
+package multiplayer
+
+import (
+	"fmt"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+// transitions contains the statuses that a slot may change to, for every status
+var transitions = map[uint8]uint8{
+	chio.SlotStatusOpen:     chio.SlotStatusNotReady | chio.SlotStatusLocked,
+	chio.SlotStatusLocked:   chio.SlotStatusOpen,
+	chio.SlotStatusNotReady: chio.SlotStatusReady | chio.SlotStatusNoMap | chio.SlotStatusPlaying | chio.SlotStatusOpen | chio.SlotStatusLocked,
+	chio.SlotStatusReady:    chio.SlotStatusNotReady | chio.SlotStatusNoMap | chio.SlotStatusPlaying | chio.SlotStatusOpen | chio.SlotStatusLocked,
+	chio.SlotStatusNoMap:    chio.SlotStatusNotReady | chio.SlotStatusOpen | chio.SlotStatusLocked,
+	chio.SlotStatusPlaying:  chio.SlotStatusComplete | chio.SlotStatusQuit,
+	chio.SlotStatusComplete: chio.SlotStatusNotReady | chio.SlotStatusQuit,
+	chio.SlotStatusQuit:     chio.SlotStatusOpen,
+}
+
+var statusNames = map[uint8]string{
+	chio.SlotStatusOpen:     "open",
+	chio.SlotStatusLocked:   "locked",
+	chio.SlotStatusNotReady: "not ready",
+	chio.SlotStatusReady:    "ready",
+	chio.SlotStatusNoMap:    "no map",
+	chio.SlotStatusPlaying:  "playing",
+	chio.SlotStatusComplete: "complete",
+	chio.SlotStatusQuit:     "quit",
+}
+
+// StatusName returns a readable name for a slot status
+func StatusName(status uint8) string {
+	if name, ok := statusNames[status]; ok {
+		return name
+	}
+	return fmt.Sprintf("SlotStatus(%d)", status)
+}
+
+// CanTransition checks if a slot may change from one status to another
+func CanTransition(from uint8, to uint8) bool {
+	return transitions[from]&to != 0
+}
+
+// transition changes the status of a slot, if the change is valid.
+// Slots without a player lose their user, mods & team.
+func transition(slot *chio.MatchSlot, status uint8) error {
+	if !CanTransition(slot.Status, status) {
+		return fmt.Errorf("%w from %s to %s", ErrInvalidTransition, StatusName(slot.Status), StatusName(status))
+	}
+
+	slot.Status = status
+	if status == chio.SlotStatusOpen || status == chio.SlotStatusLocked {
+		*slot = chio.MatchSlot{Status: status}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package chio
+
+import "fmt"
+
+// Packets that carry a single value are decoded into their own named
+// struct, so that handlers can't mix them up. Packets with a larger
+// payload decode into the matching type from types.go, e.g. OsuSendIrcMessage
+// into *Message, OsuSendUserStatus into *UserStatus or OsuMatchCreate into *Match.
+
+// Inbound packets, as they are decoded on the server side
+
+// StartSpectating is sent by the client when it starts spectating a user
+type StartSpectating struct {
+	UserId int32
+}
+
+// ErrorReport contains the text of an exception that occurred in the client
+type ErrorReport struct {
+	Text string
+}
+
+// MatchChangeSlot is sent by the client when it moves to another slot
+type MatchChangeSlot struct {
+	SlotId int32
+}
+
+// MatchLock is sent by the host to lock or unlock a slot
+type MatchLock struct {
+	SlotId int32
+}
+
+// ChannelJoin is sent by the client when it wants to join a channel
+type ChannelJoin struct {
+	Name string
+}
+
+// ChannelLeave is sent by the client when it leaves a channel
+type ChannelLeave struct {
+	Name string
+}
+
+// Outbound packets, as they are decoded on the client side
+
+// LoginReply contains the user id on success, or one of the login errors
+type LoginReply struct {
+	Reply int32
+}
+
+// IrcChangeUsername is sent when an irc user changes their name
+type IrcChangeUsername struct {
+	OldName string
+	NewName string
+}
+
+// SpectatorJoined is sent to a host when someone starts spectating them
+type SpectatorJoined struct {
+	UserId int32
+}
+
+// SpectatorLeft is sent to a host when someone stops spectating them
+type SpectatorLeft struct {
+	UserId int32
+}
+
+// SpectatorCantSpectate is sent when a spectator doesn't have the beatmap
+type SpectatorCantSpectate struct {
+	UserId int32
+}
+
+// Announcement contains a message that is shown to the user as a notification
+type Announcement struct {
+	Text string
+}
+
+// MatchDisband is sent to the lobby when a match was closed
+type MatchDisband struct {
+	MatchId int32
+}
+
+// LobbyJoin is sent to the lobby when a user joins it
+type LobbyJoin struct {
+	UserId int32
+}
+
+// LobbyPart is sent to the lobby when a user leaves it
+type LobbyPart struct {
+	UserId int32
+}
+
+// FellowSpectatorJoined is sent to spectators when someone else starts spectating
+type FellowSpectatorJoined struct {
+	UserId int32
+}
+
+// FellowSpectatorLeft is sent to spectators when someone else stops spectating
+type FellowSpectatorLeft struct {
+	UserId int32
+}
+
+// Decode returns the data of a packet as the requested type, for example:
+//
+//	message, err := chio.Decode[chio.Message](packet)
+//	spectate, err := chio.Decode[chio.StartSpectating](packet)
+//
+// An error is returned if the packet does not contain data of that type.
+// Decode is not valid for packets without a payload, e.g. OsuStopSpectating or
+// OsuCantSpectate, whose data is nil. Check the packet id of those instead.
+func Decode[T any](packet *BanchoPacket) (T, error) {
+	var zero T
+
+	switch data := packet.Data.(type) {
+	case *T:
+		if data != nil {
+			return *data, nil
+		}
+	case T:
+		return data, nil
+	}
+
+	return zero, fmt.Errorf("packet '%d' contains %T, not %T", packet.Id, packet.Data, zero)
+}
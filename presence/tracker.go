@@ -0,0 +1,183 @@
+// Package presence keeps track of the user presences that every player has
+// already received, so that only the parts that changed are sent again.
+//
+// Starting with b323, stats updates contain a flag for leaving out the stats
+// of a user, which makes status-only updates a lot smaller. A server adds
+// every connected player to a Tracker, and sends updates through it:
+//
+//	tracker.Add(&chio.Session{Id: user.Id, Name: user.Name, IO: io, Stream: conn})
+//	defer tracker.Remove(user.Id)
+//
+//	tracker.Broadcast(user.Info())
+//
+// Full stats are only sent to players that have not seen the user yet, or when
+// the stats or presence of the user have changed. The user info of the caller
+// is never modified.
+package presence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+var (
+	ErrUnknownPlayer  = errors.New("unknown player")
+	ErrMissingStatus  = errors.New("user info is missing a status")
+	ErrIncompleteInfo = errors.New("user info is missing stats or presence")
+)
+
+// snapshot is the last full user info that a player has received
+type snapshot struct {
+	name     string
+	presence chio.UserPresence
+	stats    chio.UserStats
+}
+
+// Tracker remembers which user info every player has already received
+type Tracker struct {
+	players map[int32]*chio.Session
+	seen    map[int32]map[int32]snapshot // Snapshots of every user that a player has received
+	mu      sync.Mutex
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		players: make(map[int32]*chio.Session),
+		seen:    make(map[int32]map[int32]snapshot),
+	}
+}
+
+// Add registers a player, replacing a previous player with the same id.
+// A replaced player will receive the full stats of every user again.
+func (tracker *Tracker) Add(player *chio.Session) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.players[player.Id] = player
+	tracker.seen[player.Id] = make(map[int32]snapshot)
+}
+
+// Remove handles the disconnect of a player. It is forgotten as a recipient,
+// and as a user that other players have seen.
+func (tracker *Tracker) Remove(id int32) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	delete(tracker.players, id)
+	delete(tracker.seen, id)
+	tracker.forget(id)
+}
+
+// Forget makes every player receive the full stats of a user on its next update
+func (tracker *Tracker) Forget(userId int32) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.forget(userId)
+}
+
+func (tracker *Tracker) forget(userId int32) {
+	for _, seen := range tracker.seen {
+		delete(seen, userId)
+	}
+}
+
+// Update sends the user info to a single player
+func (tracker *Tracker) Update(id int32, info chio.UserInfo) error {
+	return chio.Locked(&tracker.mu, func(outbox *chio.Outbox) error {
+		recipient, ok := tracker.players[id]
+		if !ok {
+			return fmt.Errorf("player %d: %w", id, ErrUnknownPlayer)
+		}
+		return tracker.update(outbox, recipient, info)
+	})
+}
+
+// Broadcast sends the user info to every player, including the user itself
+func (tracker *Tracker) Broadcast(info chio.UserInfo) error {
+	return chio.Locked(&tracker.mu, func(outbox *chio.Outbox) error {
+		var errs []error
+		for _, recipient := range tracker.sortedPlayers() {
+			errs = append(errs, tracker.update(outbox, recipient, info))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Seen checks if a player has already received the full stats of a user
+func (tracker *Tracker) Seen(id int32, userId int32) bool {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	_, ok := tracker.seen[id][userId]
+	return ok
+}
+
+// update sends the user info to a recipient, and leaves out the stats of the
+// user if the recipient has already received them. Missing stats & presence
+// are completed with the ones that the recipient has seen last.
+func (tracker *Tracker) update(outbox *chio.Outbox, recipient *chio.Session, info chio.UserInfo) error {
+	if info.Status == nil {
+		return fmt.Errorf("user %d: %w", info.Id, ErrMissingStatus)
+	}
+
+	status := *info.Status
+	info.Status = &status
+
+	last, seen := tracker.seen[recipient.Id][info.Id]
+	if seen {
+		info = complete(info, last)
+	}
+	if info.Stats == nil || info.Presence == nil {
+		return fmt.Errorf("user %d: %w", info.Id, ErrIncompleteInfo)
+	}
+
+	if info.Presence.IsIrc {
+		// Irc users have no stats, so there is nothing to leave out
+		return recipient.IO.WriteUserPresence(outbox.Stream(recipient), info)
+	}
+
+	current := snapshot{name: info.Name, presence: *info.Presence, stats: *info.Stats}
+	changed := !seen || current != last
+
+	if chio.Supports(recipient.IO, chio.FeatureStatusUpdates) {
+		status.UpdateStats = changed
+	} else {
+		// Older versions always send the stats, and would
+		// replace the action of the user with a stats update
+		status.UpdateStats = false
+	}
+
+	if err := recipient.IO.WriteUserStats(outbox.Stream(recipient), info); err != nil {
+		return fmt.Errorf("player %d: %w", recipient.Id, err)
+	}
+	if changed {
+		tracker.seen[recipient.Id][info.Id] = current
+	}
+	return nil
+}
+
+// complete fills in the parts of the user info that were left out by the caller
+func complete(info chio.UserInfo, last snapshot) chio.UserInfo {
+	if info.Name == "" {
+		info.Name = last.name
+	}
+	if info.Presence == nil {
+		presence := last.presence
+		info.Presence = &presence
+	}
+	if info.Stats == nil {
+		stats := last.stats
+		info.Stats = &stats
+	}
+	return info
+}
+
+func (tracker *Tracker) sortedPlayers() []*chio.Session {
+	players := make([]*chio.Session, 0, len(tracker.players))
+	for _, player := range tracker.players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].Id < players[j].Id })
+	return players
+}
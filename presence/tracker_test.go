@@ -0,0 +1,123 @@
+package presence
+
+import (
+	"errors"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/chiotest"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+func userInfo() chio.UserInfo {
+	return chio.UserInfo{
+		Id:       2,
+		Name:     "peppy",
+		Presence: &chio.UserPresence{Timezone: 9, CountryIndex: 14},
+		Status:   &chio.UserStatus{Action: chio.StatusIdle},
+		Stats:    &chio.UserStats{Rank: 1, Rscore: 1000, Tscore: 2000, Accuracy: 0.98, Playcount: 10},
+	}
+}
+
+func TestDeltaUpdates(t *testing.T) {
+	tracker := NewTracker()
+	player := chiotest.NewSession(1, clients.NewB323())
+	tracker.Add(player.Session)
+	info := userInfo()
+
+	if err := tracker.Broadcast(info); err != nil {
+		t.Fatal(err)
+	}
+	if update := chiotest.ExpectData[*chio.UserInfo](t, player.Stream, chio.BanchoHandleOsuUpdate); update.Stats == nil {
+		t.Fatal("expected full stats for an unseen user")
+	}
+	if !tracker.Seen(1, info.Id) {
+		t.Fatal("expected the user to be seen")
+	}
+
+	info.Status = &chio.UserStatus{Action: chio.StatusPlaying, Text: "DISCO PRINCE"}
+	tracker.Broadcast(info)
+	update := chiotest.ExpectData[*chio.UserInfo](t, player.Stream, chio.BanchoHandleOsuUpdate)
+	if update.Stats != nil || update.Status.Action != chio.StatusPlaying {
+		t.Fatalf("expected a status-only update, got %+v", update)
+	}
+
+	// Stats that were left out are completed from the last update
+	info.Stats = nil
+	tracker.Broadcast(info)
+	if update := chiotest.ExpectData[*chio.UserInfo](t, player.Stream, chio.BanchoHandleOsuUpdate); update.Stats != nil {
+		t.Fatal("expected a status-only update without stats")
+	}
+
+	info.Stats = &chio.UserStats{Rank: 1, Rscore: 1500, Tscore: 2500, Accuracy: 0.98, Playcount: 11}
+	tracker.Broadcast(info)
+	update = chiotest.ExpectData[*chio.UserInfo](t, player.Stream, chio.BanchoHandleOsuUpdate)
+	if update.Stats == nil || update.Stats.Rscore != 1500 {
+		t.Fatalf("expected changed stats to be sent, got %+v", update.Stats)
+	}
+	chiotest.ExpectNoError(t, player.Stream)
+}
+
+func TestOlderVersions(t *testing.T) {
+	tracker := NewTracker()
+	player := chiotest.NewSession(1, clients.NewB294())
+	tracker.Add(player.Session)
+	info := userInfo()
+
+	tracker.Broadcast(info)
+	info.Status = &chio.UserStatus{Action: chio.StatusPlaying, Text: "DISCO PRINCE"}
+	tracker.Broadcast(info)
+
+	for _, action := range []uint8{chio.StatusIdle, chio.StatusPlaying} {
+		update := chiotest.ExpectData[*chio.UserInfo](t, player.Stream, chio.BanchoHandleOsuUpdate)
+		if update.Stats == nil || update.Status.Action != action {
+			t.Fatalf("expected full stats with action %d, got %+v", action, update.Status)
+		}
+	}
+	chiotest.ExpectNoError(t, player.Stream)
+}
+
+func TestCallerNotModified(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add(chiotest.NewSession(1, clients.NewB323()).Session)
+	tracker.Add(chiotest.NewSession(3, clients.NewB294()).Session)
+
+	info := userInfo()
+	info.Status.UpdateStats = true
+	tracker.Broadcast(info)
+	tracker.Broadcast(info)
+
+	if !info.Status.UpdateStats {
+		t.Fatal("the status of the caller was modified")
+	}
+
+	// WriteUserPresence previously changed the status through its pointer
+	info.Status.UpdateStats = false
+	clients.NewB323().WriteUserPresence(chiotest.NewStream(clients.NewB323()), info)
+	if info.Status.UpdateStats {
+		t.Fatal("WriteUserPresence modified the status of the caller")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add(chiotest.NewSession(1, clients.NewB323()).Session)
+	tracker.Add(chiotest.NewSession(2, clients.NewB323()).Session)
+
+	tracker.Broadcast(userInfo())
+	tracker.Remove(2)
+
+	if tracker.Seen(1, 2) {
+		t.Fatal("removed user is still seen")
+	}
+	if err := tracker.Update(2, userInfo()); !errors.Is(err, ErrUnknownPlayer) {
+		t.Fatalf("expected ErrUnknownPlayer, got %v", err)
+	}
+
+	info := userInfo()
+	info.Id = 4
+	info.Stats = nil
+	if err := tracker.Update(1, info); !errors.Is(err, ErrIncompleteInfo) {
+		t.Fatalf("expected ErrIncompleteInfo, got %v", err)
+	}
+}
@@ -1,6 +1,13 @@
 package chio
 
-import "io"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sync"
+)
 
 // PacketReader is a function that reads packet data from a reader
 type PacketReader func(client BanchoIO, reader io.Reader) (any, error)
@@ -16,3 +23,265 @@ func InheritReaders(parent ReaderRegistry) ReaderRegistry {
 	}
 	return registry
 }
+
+// ResolveReason describes why a version was chosen by Resolve
+type ResolveReason uint8
+
+const (
+	// ResolvedNone means that the version could not be resolved
+	ResolvedNone ResolveReason = iota
+	// ResolvedExact means that the requested version is registered
+	ResolvedExact
+	// ResolvedNearestLower means that the highest version below the requested one was chosen
+	ResolvedNearestLower
+	// ResolvedLowest means that the requested version is older than every registered version
+	ResolvedLowest
+	// ResolvedHighest means that the requested version is newer than every registered version
+	ResolvedHighest
+)
+
+func (reason ResolveReason) String() string {
+	switch reason {
+	case ResolvedNone:
+		return "unresolved"
+	case ResolvedExact:
+		return "exact match"
+	case ResolvedNearestLower:
+		return "nearest lower version"
+	case ResolvedLowest:
+		return "below lowest version"
+	case ResolvedHighest:
+		return "above highest version"
+	}
+	return fmt.Sprintf("ResolveReason(%d)", uint8(reason))
+}
+
+// Resolution is the result of resolving a client version
+type Resolution struct {
+	Requested int
+	Version   int
+	Client    BanchoIO
+	Reason    ResolveReason
+}
+
+func (resolution Resolution) String() string {
+	if resolution.Reason == ResolvedNone {
+		return fmt.Sprintf("b%d (%s)", resolution.Requested, resolution.Reason)
+	}
+	return fmt.Sprintf("b%d -> b%d (%s)", resolution.Requested, resolution.Version, resolution.Reason)
+}
+
+// VersionRange is the range of client versions that resolve to a registered version
+type VersionRange struct {
+	Version int
+	From    int
+	To      int // math.MaxInt for the highest version
+}
+
+// ResolvePolicy decides how versions that were not registered exactly are resolved
+type ResolvePolicy uint8
+
+const (
+	// PolicyClamp uses the nearest lower version, and the lowest or highest
+	// version for anything outside of the registered range. Upper bounds are
+	// ignored, so this never fails unless the registry is empty.
+	PolicyClamp ResolvePolicy = iota
+	// PolicyNearestLower uses the nearest lower version, as long as the
+	// requested version is within its upper bound
+	PolicyNearestLower
+	// PolicyStrict only accepts versions that were registered exactly
+	PolicyStrict
+)
+
+func (policy ResolvePolicy) String() string {
+	switch policy {
+	case PolicyClamp:
+		return "clamp"
+	case PolicyNearestLower:
+		return "nearest-lower"
+	case PolicyStrict:
+		return "strict"
+	}
+	return fmt.Sprintf("ResolvePolicy(%d)", uint8(policy))
+}
+
+// ErrNoClients is returned when resolving a version of an empty registry
+var ErrNoClients = errors.New("no client versions registered")
+
+// ErrUnsupportedVersion is returned when a version can't be resolved with the current policy
+var ErrUnsupportedVersion = errors.New("unsupported client version")
+
+// Registry holds the client implementations for every protocol version.
+// It is safe for concurrent use.
+type Registry struct {
+	clients  map[int]BanchoIO
+	bounds   map[int]int
+	versions []int
+	policy   ResolvePolicy
+	mu       sync.RWMutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		clients: make(map[int]BanchoIO),
+		bounds:  make(map[int]int),
+	}
+}
+
+// DefaultRegistry is used by RegisterClient & GetClientInterface
+var DefaultRegistry = NewRegistry()
+
+// Register registers a client instance for a specific version without
+// an upper bound, replacing any client that was registered for it before
+func (registry *Registry) Register(version int, client BanchoIO) {
+	registry.RegisterRange(version, math.MaxInt, client)
+}
+
+// RegisterRange registers a client instance for a specific version, which is
+// known to work up to maxVersion. Versions above it will not resolve to this
+// client, unless the registry uses PolicyClamp.
+func (registry *Registry) RegisterRange(version int, maxVersion int, client BanchoIO) error {
+	if maxVersion < version {
+		return fmt.Errorf("upper bound b%d is below b%d", maxVersion, version)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.clients[version]; !ok {
+		index, _ := slices.BinarySearch(registry.versions, version)
+		registry.versions = slices.Insert(registry.versions, index, version)
+	}
+	registry.clients[version] = client
+	registry.bounds[version] = maxVersion
+	return nil
+}
+
+// SetUpperBound changes the highest version that a registered version can be used for
+func (registry *Registry) SetUpperBound(version int, maxVersion int) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.clients[version]; !ok {
+		return fmt.Errorf("b%d is not registered", version)
+	}
+	if maxVersion < version {
+		return fmt.Errorf("upper bound b%d is below b%d", maxVersion, version)
+	}
+
+	registry.bounds[version] = maxVersion
+	return nil
+}
+
+// SetPolicy changes how Resolve handles versions that were not registered exactly
+func (registry *Registry) SetPolicy(policy ResolvePolicy) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.policy = policy
+}
+
+// Policy returns the policy that is used by Resolve
+func (registry *Registry) Policy() ResolvePolicy {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.policy
+}
+
+// Get returns the client that was registered for exactly this version
+func (registry *Registry) Get(version int) (BanchoIO, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	client, ok := registry.clients[version]
+	return client, ok
+}
+
+// Versions returns every registered version in ascending order
+func (registry *Registry) Versions() []int {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return slices.Clone(registry.versions)
+}
+
+// Ranges returns the range of versions that each registered version is used for,
+// limited by their upper bounds. Versions below the lowest registered version and
+// versions that are only reachable through PolicyClamp are not part of any range.
+func (registry *Registry) Ranges() []VersionRange {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	ranges := make([]VersionRange, len(registry.versions))
+	for i, version := range registry.versions {
+		ranges[i] = VersionRange{Version: version, From: version, To: registry.bounds[version]}
+		if i+1 < len(registry.versions) {
+			ranges[i].To = min(ranges[i].To, registry.versions[i+1]-1)
+		}
+	}
+	return ranges
+}
+
+// Resolve returns the client that should be used for a version, using the policy of the registry
+func (registry *Registry) Resolve(version int) (Resolution, error) {
+	return registry.ResolveWith(version, registry.Policy())
+}
+
+// ResolveWith returns the client that should be used for a version, using the provided policy.
+// Unless the version was registered exactly, the highest registered version that is lower than
+// the requested one is chosen. Versions outside of the registered range are handled by the policy.
+func (registry *Registry) ResolveWith(version int, policy ResolvePolicy) (Resolution, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	resolution := Resolution{Requested: version}
+	if len(registry.versions) == 0 {
+		return resolution, ErrNoClients
+	}
+
+	lowestVersion := registry.versions[0]
+	highestVersion := registry.versions[len(registry.versions)-1]
+
+	switch {
+	case version < lowestVersion:
+		resolution.Version = lowestVersion
+		resolution.Reason = ResolvedLowest
+	case version > highestVersion:
+		resolution.Version = highestVersion
+		resolution.Reason = ResolvedHighest
+	default:
+		// Find the highest version that is <= version
+		index, found := slices.BinarySearch(registry.versions, version)
+		if !found {
+			index--
+		}
+		resolution.Version = registry.versions[index]
+		resolution.Reason = ResolvedNearestLower
+		if found {
+			resolution.Reason = ResolvedExact
+		}
+	}
+
+	switch policy {
+	case PolicyStrict:
+		if resolution.Reason != ResolvedExact {
+			return Resolution{Requested: version}, fmt.Errorf("%w b%d: not registered", ErrUnsupportedVersion, version)
+		}
+	case PolicyNearestLower:
+		if resolution.Reason == ResolvedLowest {
+			return Resolution{Requested: version}, fmt.Errorf("%w b%d: older than b%d", ErrUnsupportedVersion, version, lowestVersion)
+		}
+		if bound := registry.bounds[resolution.Version]; version > bound {
+			return Resolution{Requested: version}, fmt.Errorf("%w b%d: newer than b%d, which is valid up to b%d", ErrUnsupportedVersion, version, resolution.Version, bound)
+		}
+	}
+
+	resolution.Client = registry.clients[resolution.Version]
+	return resolution, nil
+}
+
+// GetClientInterface returns the client for a version, or nil if it can't be resolved
+func (registry *Registry) GetClientInterface(version int) BanchoIO {
+	resolution, err := registry.Resolve(version)
+	if err != nil {
+		return nil
+	}
+	return resolution.Client
+}
@@ -0,0 +1,127 @@
+package chio_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+func TestResolvePolicies(t *testing.T) {
+	registry := chio.NewRegistry()
+	registry.RegisterRange(282, 289, clients.NewB282())
+	registry.RegisterRange(294, 300, clients.NewB294())
+
+	tests := []struct {
+		name    string
+		policy  chio.ResolvePolicy
+		version int
+		want    int // 0 if the version is unsupported
+		reason  chio.ResolveReason
+	}{
+		{"clamp below", chio.PolicyClamp, 100, 282, chio.ResolvedLowest},
+		{"clamp nearest", chio.PolicyClamp, 285, 282, chio.ResolvedNearestLower},
+		{"clamp gap", chio.PolicyClamp, 290, 282, chio.ResolvedNearestLower},
+		{"clamp exact", chio.PolicyClamp, 294, 294, chio.ResolvedExact},
+		{"clamp above bound", chio.PolicyClamp, 400, 294, chio.ResolvedHighest},
+
+		{"nearest-lower below", chio.PolicyNearestLower, 100, 0, chio.ResolvedNone},
+		{"nearest-lower nearest", chio.PolicyNearestLower, 285, 282, chio.ResolvedNearestLower},
+		{"nearest-lower gap", chio.PolicyNearestLower, 290, 0, chio.ResolvedNone},
+		{"nearest-lower exact", chio.PolicyNearestLower, 294, 294, chio.ResolvedExact},
+		{"nearest-lower above bound", chio.PolicyNearestLower, 400, 0, chio.ResolvedNone},
+
+		{"strict below", chio.PolicyStrict, 100, 0, chio.ResolvedNone},
+		{"strict nearest", chio.PolicyStrict, 285, 0, chio.ResolvedNone},
+		{"strict gap", chio.PolicyStrict, 290, 0, chio.ResolvedNone},
+		{"strict exact", chio.PolicyStrict, 294, 294, chio.ResolvedExact},
+		{"strict above bound", chio.PolicyStrict, 400, 0, chio.ResolvedNone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolution, err := registry.ResolveWith(test.version, test.policy)
+
+			if test.want == 0 {
+				if !errors.Is(err, chio.ErrUnsupportedVersion) {
+					t.Fatalf("expected ErrUnsupportedVersion, got %v (%s)", err, resolution)
+				}
+				if resolution.Client != nil || resolution.Reason != chio.ResolvedNone {
+					t.Fatalf("expected an empty resolution, got %s", resolution)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resolution.Version != test.want || resolution.Reason != test.reason || resolution.Client == nil {
+				t.Fatalf("expected b%d (%s), got %s", test.want, test.reason, resolution)
+			}
+		})
+	}
+}
+
+func TestResolveEmptyRegistry(t *testing.T) {
+	registry := chio.NewRegistry()
+
+	for _, policy := range []chio.ResolvePolicy{chio.PolicyClamp, chio.PolicyNearestLower, chio.PolicyStrict} {
+		if _, err := registry.ResolveWith(282, policy); !errors.Is(err, chio.ErrNoClients) {
+			t.Errorf("%s: expected ErrNoClients, got %v", policy, err)
+		}
+	}
+	if registry.GetClientInterface(282) != nil {
+		t.Error("expected no client for an empty registry")
+	}
+}
+
+func TestRegisterRange(t *testing.T) {
+	registry := chio.NewRegistry()
+
+	if err := registry.RegisterRange(294, 293, clients.NewB294()); err == nil {
+		t.Fatal("expected an error for an upper bound below the version")
+	}
+	if len(registry.Versions()) != 0 {
+		t.Fatal("invalid range was registered")
+	}
+
+	registry.Register(282, clients.NewB282())
+	registry.RegisterRange(294, 300, clients.NewB294())
+
+	ranges := registry.Ranges()
+	expected := []chio.VersionRange{{Version: 282, From: 282, To: 293}, {Version: 294, From: 294, To: 300}}
+	if len(ranges) != len(expected) || ranges[0] != expected[0] || ranges[1] != expected[1] {
+		t.Fatalf("expected ranges %v, got %v", expected, ranges)
+	}
+
+	if err := registry.SetUpperBound(282, math.MaxInt); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.SetUpperBound(283, 300); err == nil {
+		t.Fatal("expected an error for an unregistered version")
+	}
+}
+
+func TestBuiltinUpperBounds(t *testing.T) {
+	if _, err := chio.ResolveClientInterface(20150101, chio.PolicyNearestLower); !errors.Is(err, chio.ErrUnsupportedVersion) {
+		t.Fatalf("expected b20150101 to be unsupported, got %v", err)
+	}
+
+	// Versions after b323 have not been verified
+	if _, err := chio.ResolveClientInterface(325, chio.PolicyNearestLower); !errors.Is(err, chio.ErrUnsupportedVersion) {
+		t.Fatalf("expected b325 to be unsupported, got %v", err)
+	}
+
+	resolution, err := chio.DefaultRegistry.ResolveWith(325, chio.PolicyClamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolution.Version != 323 || resolution.Reason != chio.ResolvedHighest {
+		t.Fatalf("expected b325 to be clamped to b323, got %s", resolution)
+	}
+	if _, ok := resolution.Client.(*clients.B323); !ok {
+		t.Fatalf("expected b323, got %T", resolution.Client)
+	}
+}
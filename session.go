@@ -0,0 +1,83 @@
+package chio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Session is a connected player, as it is shared between the spectator,
+// multiplayer, chat & presence packages. The stream may be written to
+// concurrently, if those are used from multiple goroutines.
+type Session struct {
+	Id     int32
+	Name   string
+	IO     BanchoIO
+	Stream io.Writer
+}
+
+// Recipient returns the destination of a broadcast to the session
+func (session *Session) Recipient() Recipient {
+	return Recipient{IO: session.IO, Stream: session.Stream}
+}
+
+// Outbox buffers the packets for sessions while a lock is held, so that a slow
+// stream doesn't block everyone else that needs the lock. The packets of every
+// session keep their order, but packets of concurrent calls may be flushed in
+// either order.
+type Outbox struct {
+	sessions []*Session
+	buffers  map[*Session]*bytes.Buffer
+}
+
+// Stream returns the buffer for the packets of the session
+func (outbox *Outbox) Stream(session *Session) io.Writer {
+	if outbox.buffers == nil {
+		outbox.buffers = make(map[*Session]*bytes.Buffer)
+	}
+	buffer, ok := outbox.buffers[session]
+	if !ok {
+		buffer = new(bytes.Buffer)
+		outbox.buffers[session] = buffer
+		outbox.sessions = append(outbox.sessions, session)
+	}
+	return buffer
+}
+
+// Recipient returns the destination of a broadcast to the buffer of the session
+func (outbox *Outbox) Recipient(session *Session) Recipient {
+	return Recipient{IO: session.IO, Stream: outbox.Stream(session)}
+}
+
+// Flush writes the buffered packets to the stream of every session, in the
+// order that they were first written to. Errors of individual sessions do
+// not stop the flush, and are returned together.
+func (outbox *Outbox) Flush() error {
+	var errs []error
+	for _, session := range outbox.sessions {
+		buffer := outbox.buffers[session]
+		if buffer.Len() == 0 {
+			continue
+		}
+		if _, err := session.Stream.Write(buffer.Bytes()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	outbox.sessions = nil
+	outbox.buffers = nil
+	return errors.Join(errs...)
+}
+
+// Locked calls fn while holding the lock, and flushes
+// the packets that it wrote to the outbox afterwards
+func Locked(mu sync.Locker, fn func(outbox *Outbox) error) error {
+	outbox := new(Outbox)
+	err := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return fn(outbox)
+	}()
+	return errors.Join(err, outbox.Flush())
+}
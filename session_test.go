@@ -0,0 +1,73 @@
+package chio_test
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/chiotest"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+// lockedStream records whether the lock was held while it was written to
+type lockedStream struct {
+	*chiotest.Stream
+	mu     *sync.Mutex
+	locked bool
+}
+
+func (stream *lockedStream) Write(p []byte) (int, error) {
+	if stream.mu.TryLock() {
+		stream.mu.Unlock()
+	} else {
+		stream.locked = true
+	}
+	return stream.Stream.Write(p)
+}
+
+func TestLockedFlushesAfterUnlock(t *testing.T) {
+	mu := new(sync.Mutex)
+	client := clients.NewB323()
+	stream := &lockedStream{Stream: chiotest.NewStream(client), mu: mu}
+	session := &chio.Session{Id: 1, IO: client, Stream: stream}
+
+	err := chio.Locked(mu, func(outbox *chio.Outbox) error {
+		client.WriteSpectatorJoined(outbox.Stream(session), 2)
+		return chio.Broadcast([]chio.Recipient{outbox.Recipient(session)}, func(client chio.BanchoIO, stream io.Writer) error {
+			return client.WriteSpectatorLeft(stream, 2)
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stream.locked {
+		t.Fatal("expected the stream to be written to after the lock was released")
+	}
+
+	chiotest.ExpectNext(t, stream.Stream, chio.BanchoSpectatorJoined)
+	chiotest.ExpectNext(t, stream.Stream, chio.BanchoSpectatorLeft)
+	chiotest.ExpectNone(t, stream.Stream)
+}
+
+func TestOutboxFlushErrors(t *testing.T) {
+	client := clients.NewB323()
+	failing := &chio.Session{Id: 1, IO: client, Stream: failingStream{}}
+	working := chiotest.NewSession(2, client)
+
+	outbox := new(chio.Outbox)
+	client.WritePing(outbox.Stream(failing))
+	client.WritePing(outbox.Stream(working.Session))
+
+	if err := outbox.Flush(); !errors.Is(err, errStream) {
+		t.Fatalf("expected the stream error, got %v", err)
+	}
+	chiotest.ExpectNext(t, working.Stream, chio.BanchoPing)
+
+	// Packets are only written once
+	if err := outbox.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	chiotest.ExpectNone(t, working.Stream)
+}
@@ -0,0 +1,244 @@
+// Package spectator keeps track of which players are spectating whom, and
+// sends the spectator packets of the bancho protocol on behalf of a server.
+//
+// A server adds every connected player to a Hub, and passes the spectator
+// packets it reads from them to Handle:
+//
+//	hub.Add(&chio.Session{Id: user.Id, Name: user.Name, IO: io, Stream: conn})
+//	defer hub.Remove(user.Id)
+//
+//	for {
+//		packet, err := io.ReadPacket(conn)
+//		...
+//		hub.Handle(user.Id, packet)
+//	}
+//
+// Replay frames of a host are converted for the versions of its spectators,
+// and encoded once per version.
+package spectator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	chio "github.com/Lekuruu/chio-go"
+)
+
+var ErrUnknownPlayer = errors.New("unknown player")
+
+// Hub tracks hosts & their spectators
+type Hub struct {
+	players    map[int32]*chio.Session
+	spectators map[int32][]int32 // Spectators of a host, in the order they joined
+	hosts      map[int32]int32   // Host that a spectator is watching
+	mu         sync.Mutex
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		players:    make(map[int32]*chio.Session),
+		spectators: make(map[int32][]int32),
+		hosts:      make(map[int32]int32),
+	}
+}
+
+// Add registers a player, replacing a previous player with the same id
+func (hub *Hub) Add(player *chio.Session) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hub.players[player.Id] = player
+}
+
+// Remove handles the disconnect of a player. Players that were spectating it
+// are detached, and notified that their fellow spectators left. They are
+// returned, so that the server can let them know that the host is gone.
+func (hub *Hub) Remove(id int32) ([]*chio.Session, error) {
+	var spectators []*chio.Session
+
+	err := chio.Locked(&hub.mu, func(outbox *chio.Outbox) error {
+		errs := []error{hub.stop(outbox, id)}
+		spectators = hub.spectatorsOf(id)
+
+		for _, spectator := range spectators {
+			delete(hub.hosts, spectator.Id)
+
+			for _, fellow := range spectators {
+				if fellow.Id != spectator.Id {
+					errs = append(errs, spectator.IO.WriteFellowSpectatorLeft(outbox.Stream(spectator), fellow.Id))
+				}
+			}
+		}
+
+		delete(hub.spectators, id)
+		delete(hub.players, id)
+		return errors.Join(errs...)
+	})
+	return spectators, err
+}
+
+// Handle applies a spectator packet that was read from a player. Other packets are ignored.
+func (hub *Hub) Handle(id int32, packet *chio.BanchoPacket) error {
+	switch packet.Id {
+	case chio.OsuStartSpectating:
+		spectate, err := chio.Decode[chio.StartSpectating](packet)
+		if err != nil {
+			return err
+		}
+		return hub.Start(id, spectate.UserId)
+	case chio.OsuStopSpectating:
+		return hub.Stop(id)
+	case chio.OsuSpectateFrames:
+		bundle, err := chio.Decode[chio.ReplayFrameBundle](packet)
+		if err != nil {
+			return err
+		}
+		return hub.Frames(id, bundle)
+	case chio.OsuCantSpectate:
+		return hub.CantSpectate(id)
+	}
+	return nil
+}
+
+// Start lets a player spectate the host. If it was spectating someone else
+// before, it stops doing so first. The host is notified before the fellow
+// spectators, which are introduced to the new spectator and the other way around.
+func (hub *Hub) Start(id int32, hostId int32) error {
+	return chio.Locked(&hub.mu, func(outbox *chio.Outbox) error {
+		spectator, ok := hub.players[id]
+		if !ok {
+			return fmt.Errorf("spectator %d: %w", id, ErrUnknownPlayer)
+		}
+		host, ok := hub.players[hostId]
+		if !ok {
+			return fmt.Errorf("host %d: %w", hostId, ErrUnknownPlayer)
+		}
+		if id == hostId {
+			return fmt.Errorf("player %d can't spectate itself", id)
+		}
+		if current, ok := hub.hosts[id]; ok && current == hostId {
+			return nil
+		}
+
+		errs := []error{hub.stop(outbox, id)}
+		errs = append(errs, host.IO.WriteSpectatorJoined(outbox.Stream(host), id))
+
+		for _, fellow := range hub.spectatorsOf(hostId) {
+			errs = append(errs, fellow.IO.WriteFellowSpectatorJoined(outbox.Stream(fellow), id))
+			errs = append(errs, spectator.IO.WriteFellowSpectatorJoined(outbox.Stream(spectator), fellow.Id))
+		}
+
+		hub.spectators[hostId] = append(hub.spectators[hostId], id)
+		hub.hosts[id] = hostId
+		return errors.Join(errs...)
+	})
+}
+
+// Stop lets a player stop spectating, which is reported to the host and fellow spectators
+func (hub *Hub) Stop(id int32) error {
+	return chio.Locked(&hub.mu, func(outbox *chio.Outbox) error {
+		return hub.stop(outbox, id)
+	})
+}
+
+func (hub *Hub) stop(outbox *chio.Outbox, id int32) error {
+	hostId, ok := hub.hosts[id]
+	if !ok {
+		return nil
+	}
+	delete(hub.hosts, id)
+
+	spectators := hub.spectators[hostId]
+	for i, spectator := range spectators {
+		if spectator == id {
+			spectators = append(spectators[:i:i], spectators[i+1:]...)
+			break
+		}
+	}
+	if len(spectators) == 0 {
+		delete(hub.spectators, hostId)
+	} else {
+		hub.spectators[hostId] = spectators
+	}
+
+	errs := make([]error, 0)
+	if host, ok := hub.players[hostId]; ok {
+		errs = append(errs, host.IO.WriteSpectatorLeft(outbox.Stream(host), id))
+	}
+	for _, fellow := range hub.spectatorsOf(hostId) {
+		errs = append(errs, fellow.IO.WriteFellowSpectatorLeft(outbox.Stream(fellow), id))
+	}
+	return errors.Join(errs...)
+}
+
+// Frames sends the replay frames of a host to its spectators. The bundle is
+// converted & encoded once for every client version among the spectators.
+func (hub *Hub) Frames(hostId int32, bundle chio.ReplayFrameBundle) error {
+	hub.mu.Lock()
+	host, ok := hub.players[hostId]
+	spectators := hub.spectatorsOf(hostId)
+	hub.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("host %d: %w", hostId, ErrUnknownPlayer)
+	}
+
+	recipients := make([]chio.Recipient, len(spectators))
+	for i, spectator := range spectators {
+		recipients[i] = spectator.Recipient()
+	}
+
+	return chio.Broadcast(recipients, func(client chio.BanchoIO, stream io.Writer) error {
+		return chio.RelayFrames(stream, bundle, host.IO, client)
+	})
+}
+
+// CantSpectate reports to the host and fellow spectators, that
+// the player is missing the beatmap that is being played
+func (hub *Hub) CantSpectate(id int32) error {
+	return chio.Locked(&hub.mu, func(outbox *chio.Outbox) error {
+		hostId, ok := hub.hosts[id]
+		if !ok {
+			return nil
+		}
+
+		errs := make([]error, 0)
+		if host, ok := hub.players[hostId]; ok {
+			errs = append(errs, host.IO.WriteSpectatorCantSpectate(outbox.Stream(host), id))
+		}
+		for _, fellow := range hub.spectatorsOf(hostId) {
+			if fellow.Id != id {
+				errs = append(errs, fellow.IO.WriteSpectatorCantSpectate(outbox.Stream(fellow), id))
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Spectators returns the ids of the players that are spectating the host
+func (hub *Hub) Spectators(hostId int32) []int32 {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	return append([]int32{}, hub.spectators[hostId]...)
+}
+
+// Host returns the id of the player that is being spectated, if any
+func (hub *Hub) Host(id int32) (int32, bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hostId, ok := hub.hosts[id]
+	return hostId, ok
+}
+
+func (hub *Hub) spectatorsOf(hostId int32) []*chio.Session {
+	ids := hub.spectators[hostId]
+	players := make([]*chio.Session, 0, len(ids))
+
+	for _, id := range ids {
+		if player, ok := hub.players[id]; ok {
+			players = append(players, player)
+		}
+	}
+	return players
+}
@@ -0,0 +1,219 @@
+package spectator
+
+import (
+	"io"
+	"testing"
+
+	chio "github.com/Lekuruu/chio-go"
+	"github.com/Lekuruu/chio-go/chiotest"
+	"github.com/Lekuruu/chio-go/clients"
+)
+
+func expectUser[T any](t *testing.T, stream *chiotest.Stream, packetId uint16, userId int32, get func(T) int32) {
+	t.Helper()
+	packet := chiotest.ExpectNext(t, stream, packetId)
+	data, err := chio.Decode[T](packet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := get(data); id != userId {
+		t.Fatalf("expected %s for user %d, got %d", chio.PacketName(packetId), userId, id)
+	}
+}
+
+func TestHubJoinOrder(t *testing.T) {
+	hub := NewHub()
+	host := chiotest.NewSession(1, clients.NewB323())
+	hub.Add(host.Session)
+	first := chiotest.NewSession(2, clients.NewB323())
+	hub.Add(first.Session)
+	second := chiotest.NewSession(3, clients.NewB323())
+	hub.Add(second.Session)
+
+	if err := hub.Start(2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := hub.Start(3, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	joined := func(data chio.SpectatorJoined) int32 { return data.UserId }
+	fellowJoined := func(data chio.FellowSpectatorJoined) int32 { return data.UserId }
+
+	expectUser(t, host.Stream, chio.BanchoSpectatorJoined, 2, joined)
+	expectUser(t, host.Stream, chio.BanchoSpectatorJoined, 3, joined)
+	expectUser(t, first.Stream, chio.BanchoFellowSpectatorJoined, 3, fellowJoined)
+	expectUser(t, second.Stream, chio.BanchoFellowSpectatorJoined, 2, fellowJoined)
+	chiotest.ExpectNone(t, host.Stream)
+
+	if err := hub.Stop(2); err != nil {
+		t.Fatal(err)
+	}
+	expectUser(t, host.Stream, chio.BanchoSpectatorLeft, 2, func(data chio.SpectatorLeft) int32 { return data.UserId })
+	expectUser(t, second.Stream, chio.BanchoFellowSpectatorLeft, 2, func(data chio.FellowSpectatorLeft) int32 { return data.UserId })
+	chiotest.ExpectNone(t, first.Stream)
+
+	if spectators := hub.Spectators(1); len(spectators) != 1 || spectators[0] != 3 {
+		t.Fatalf("expected spectators [3], got %v", spectators)
+	}
+}
+
+func TestHubFrames(t *testing.T) {
+	hub := NewHub()
+	hub.Add(chiotest.NewSession(1, clients.NewB282()).Session)
+	modern := chiotest.NewSession(2, clients.NewB323())
+	hub.Add(modern.Session)
+	old := chiotest.NewSession(3, clients.NewB294())
+	hub.Add(old.Session)
+
+	hub.Start(2, 1)
+	hub.Start(3, 1)
+	modern.Stream.Reset()
+	old.Stream.Reset()
+
+	bundle := chio.ReplayFrameBundle{
+		Action: chio.ReplayActionStandard,
+		Frames: []*chio.ReplayFrame{{ButtonState: chio.ButtonStateLeft2, MouseX: 256, MouseY: 192, Time: 100}},
+	}
+	if err := hub.Frames(1, bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, stream := range []*chiotest.Stream{modern.Stream, old.Stream} {
+		chiotest.ExpectWhere(t, stream, chio.BanchoSpectateFrames, func(bundle chio.ReplayFrameBundle) bool {
+			return len(bundle.Frames) == 1 && bundle.Frames[0].ButtonState == chio.ButtonStateLeft1
+		})
+		chiotest.ExpectNoError(t, stream)
+	}
+}
+
+// countingIO counts how often frames are encoded for its spectators
+type countingIO struct {
+	*clients.B323
+	encodes int
+}
+
+func (client *countingIO) WriteSpectateFrames(stream io.Writer, bundle chio.ReplayFrameBundle) error {
+	client.encodes++
+	return client.B323.WriteSpectateFrames(stream, bundle)
+}
+
+func TestHubFramesEncodeOnce(t *testing.T) {
+	hub := NewHub()
+	shared := &countingIO{B323: clients.NewB323()}
+	other := &countingIO{B323: clients.NewB323()}
+
+	hub.Add(chiotest.NewSession(1, clients.NewB323()).Session)
+	spectators := []*chiotest.Session{chiotest.NewSession(2, shared), chiotest.NewSession(3, shared), chiotest.NewSession(4, other)}
+	for _, spectator := range spectators {
+		hub.Add(spectator.Session)
+		hub.Start(spectator.Id, 1)
+	}
+
+	bundle := chio.ReplayFrameBundle{Frames: []*chio.ReplayFrame{{MouseX: 256, MouseY: 192, Time: 100}}}
+	if err := hub.Frames(1, bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	if shared.encodes != 1 || other.encodes != 1 {
+		t.Fatalf("expected one encode per client, got %d and %d", shared.encodes, other.encodes)
+	}
+	for _, spectator := range spectators {
+		chiotest.ExpectWhere(t, spectator.Stream, chio.BanchoSpectateFrames, func(bundle chio.ReplayFrameBundle) bool {
+			return len(bundle.Frames) == 1
+		})
+	}
+}
+
+func TestHubHostDisconnect(t *testing.T) {
+	hub := NewHub()
+	hub.Add(chiotest.NewSession(1, clients.NewB323()).Session)
+	first := chiotest.NewSession(2, clients.NewB323())
+	hub.Add(first.Session)
+	hub.Add(chiotest.NewSession(3, clients.NewB323()).Session)
+
+	hub.Start(2, 1)
+	hub.Start(3, 1)
+	first.Stream.Reset()
+
+	spectators, err := hub.Remove(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spectators) != 2 {
+		t.Fatalf("expected 2 detached spectators, got %d", len(spectators))
+	}
+	expectUser(t, first.Stream, chio.BanchoFellowSpectatorLeft, 3, func(data chio.FellowSpectatorLeft) int32 { return data.UserId })
+
+	if _, ok := hub.Host(2); ok {
+		t.Fatal("spectator is still attached to the host")
+	}
+	if err := hub.Start(2, 1); err == nil {
+		t.Fatal("expected an error when spectating a removed host")
+	}
+}
+
+func TestHubHandle(t *testing.T) {
+	hub := NewHub()
+	host := chiotest.NewSession(1, clients.NewB298())
+	hub.Add(host.Session)
+	hub.Add(chiotest.NewSession(2, clients.NewB298()).Session)
+
+	client := chiotest.NewClient(clients.NewB298())
+	client.Osu.WriteStartSpectating(client, 1)
+	client.Osu.WriteCantSpectate(client)
+
+	packets, err := client.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, packet := range packets {
+		if err := hub.Handle(2, packet); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chiotest.ExpectNext(t, host.Stream, chio.BanchoSpectatorJoined)
+	chiotest.ExpectNext(t, host.Stream, chio.BanchoSpectatorCantSpectate)
+}
+
+// hubStream records whether the hub was locked while it was written to
+type hubStream struct {
+	*chiotest.Stream
+	hub    *Hub
+	locked bool
+}
+
+func (stream *hubStream) Write(p []byte) (int, error) {
+	if stream.hub.mu.TryLock() {
+		stream.hub.mu.Unlock()
+	} else {
+		stream.locked = true
+	}
+	return stream.Stream.Write(p)
+}
+
+func TestHubWritesWithoutLock(t *testing.T) {
+	hub := NewHub()
+	streams := make([]*hubStream, 0)
+	for id := int32(1); id <= 3; id++ {
+		stream := &hubStream{Stream: chiotest.NewStream(clients.NewB323()), hub: hub}
+		hub.Add(&chio.Session{Id: id, IO: stream.IO, Stream: stream})
+		streams = append(streams, stream)
+	}
+
+	hub.Start(2, 1)
+	hub.Start(3, 1)
+	hub.CantSpectate(3)
+	hub.Stop(3)
+	hub.Remove(1)
+
+	for i, stream := range streams {
+		if stream.locked {
+			t.Fatalf("player %d was written to while the hub was locked", i+1)
+		}
+		if len(stream.Packets()) == 0 {
+			t.Fatalf("player %d didn't receive any packets", i+1)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package chio
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PacketTable maps the packet ids of this package to the ids
+// that a specific client version uses on the wire, and back
+type PacketTable struct {
+	toWire   map[uint16]uint16
+	fromWire map[uint16]uint16
+}
+
+// NewPacketTable creates a table from a map of packet ids to wire ids
+func NewPacketTable(ids map[uint16]uint16) *PacketTable {
+	table := &PacketTable{
+		toWire:   make(map[uint16]uint16, len(ids)),
+		fromWire: make(map[uint16]uint16, len(ids)),
+	}
+	for packetId, wireId := range ids {
+		table.toWire[packetId] = wireId
+		table.fromWire[wireId] = packetId
+	}
+	return table
+}
+
+// Extend creates a copy of the table with additional packets.
+// Packets that already exist in the table will be remapped.
+func (table *PacketTable) Extend(ids map[uint16]uint16) *PacketTable {
+	merged := make(map[uint16]uint16, len(table.toWire)+len(ids))
+	for packetId, wireId := range table.toWire {
+		merged[packetId] = wireId
+	}
+	for packetId, wireId := range ids {
+		merged[packetId] = wireId
+	}
+	return NewPacketTable(merged)
+}
+
+// WireId returns the id that is used on the wire for the packet
+func (table *PacketTable) WireId(packetId uint16) (uint16, bool) {
+	wireId, ok := table.toWire[packetId]
+	return wireId, ok
+}
+
+// PacketId returns the packet id for an id that was read from the wire
+func (table *PacketTable) PacketId(wireId uint16) (uint16, bool) {
+	packetId, ok := table.fromWire[wireId]
+	return packetId, ok
+}
+
+// Contains checks if the packet is part of the table
+func (table *PacketTable) Contains(packetId uint16) bool {
+	_, ok := table.toWire[packetId]
+	return ok
+}
+
+// Packets returns all packet ids of the table in ascending order
+func (table *PacketTable) Packets() []uint16 {
+	ids := make([]uint16, 0, len(table.toWire))
+	for packetId := range table.toWire {
+		ids = append(ids, packetId)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Validate checks that every packet converts to a unique wire id and back,
+// i.e. that no two packets were mapped to the same wire id
+func (table *PacketTable) Validate() error {
+	for _, packetId := range table.Packets() {
+		wireId := table.toWire[packetId]
+		if other := table.fromWire[wireId]; other != packetId {
+			return fmt.Errorf(
+				"%s and %s both use wire id %d",
+				PacketName(other), PacketName(packetId), wireId,
+			)
+		}
+	}
+	return nil
+}